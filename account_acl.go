@@ -0,0 +1,129 @@
+package goswift
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// AccountACL is the JSON value of X-Account-Access-Control. Each list holds
+// user or group identities as understood by the auth system, e.g. Keystone
+// project:user ids or tempauth account names.
+type AccountACL struct {
+	Admin     []string `json:"admin,omitempty"`
+	ReadWrite []string `json:"read-write,omitempty"`
+	ReadOnly  []string `json:"read-only,omitempty"`
+}
+
+// ParseAccountACL parses a X-Account-Access-Control value. An empty value
+// is an empty ACL.
+func ParseAccountACL(s string) (AccountACL, error) {
+	var acl AccountACL
+	if s == "" {
+		return acl, nil
+	}
+	if err := json.Unmarshal([]byte(s), &acl); err != nil {
+		return acl, fmt.Errorf("Invalid X-Account-Access-Control: %s", err)
+	}
+	return acl, nil
+}
+
+func (a AccountACL) String() string {
+	b, _ := json.Marshal(a)
+	return string(b)
+}
+
+func (a AccountACL) IsEmpty() bool {
+	return len(a.Admin) == 0 && len(a.ReadWrite) == 0 && len(a.ReadOnly) == 0
+}
+
+// Merge returns the union of a and other.
+func (a AccountACL) Merge(other AccountACL) AccountACL {
+	return AccountACL{
+		Admin:     mergeStrings(a.Admin, other.Admin),
+		ReadWrite: mergeStrings(a.ReadWrite, other.ReadWrite),
+		ReadOnly:  mergeStrings(a.ReadOnly, other.ReadOnly),
+	}
+}
+
+// Remove returns a without the identities listed in other.
+func (a AccountACL) Remove(other AccountACL) AccountACL {
+	return AccountACL{
+		Admin:     removeStrings(a.Admin, other.Admin),
+		ReadWrite: removeStrings(a.ReadWrite, other.ReadWrite),
+		ReadOnly:  removeStrings(a.ReadOnly, other.ReadOnly),
+	}
+}
+
+func mergeStrings(a, b []string) []string {
+	var merged []string
+	seen := make(map[string]bool)
+	for _, l := range [][]string{a, b} {
+		for _, s := range l {
+			if !seen[s] {
+				seen[s] = true
+				merged = append(merged, s)
+			}
+		}
+	}
+	return merged
+}
+
+func removeStrings(a, b []string) []string {
+	drop := make(map[string]bool)
+	for _, s := range b {
+		drop[s] = true
+	}
+	var kept []string
+	for _, s := range a {
+		if !drop[s] {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// SetAccountACL adds the header that replaces the account ACL. An empty ACL
+// removes it.
+func (m *Metadata) SetAccountACL(acl AccountACL) {
+	if acl.IsEmpty() {
+		m.SetDeleteMeta("X-Account-Access-Control")
+	} else {
+		m.SetMeta("X-Account-Access-Control", acl.String())
+	}
+}
+
+// GetAccountACL returns the account ACL. Swift only shows it to account
+// owners, so other users get an empty ACL.
+func (c *Client) GetAccountACL() (AccountACL, error) {
+	header, err := c.ShowAccountMeta()
+	if err != nil {
+		return AccountACL{}, err
+	}
+	return ParseAccountACL(http.Header(header).Get("X-Account-Access-Control"))
+}
+
+func (c *Client) SetAccountACL(acl AccountACL) (http.Header, error) {
+	metadata := NewMetadata()
+	metadata.SetAccountACL(acl)
+	return c.CreateAccountMeta(metadata)
+}
+
+// MergeAccountACL adds the identities of acl to the current account ACL.
+func (c *Client) MergeAccountACL(acl AccountACL) (http.Header, error) {
+	current, err := c.GetAccountACL()
+	if err != nil {
+		return nil, err
+	}
+	return c.SetAccountACL(current.Merge(acl))
+}
+
+// RevokeAccountACL removes the identities of acl from the current account
+// ACL.
+func (c *Client) RevokeAccountACL(acl AccountACL) (http.Header, error) {
+	current, err := c.GetAccountACL()
+	if err != nil {
+		return nil, err
+	}
+	return c.SetAccountACL(current.Remove(acl))
+}
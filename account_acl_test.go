@@ -0,0 +1,35 @@
+package goswift
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAccountACLMerge(t *testing.T) {
+	acl, err := ParseAccountACL(`{"admin":["AUTH_alice"],"read-only":["*"]}`)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	acl = acl.Merge(AccountACL{Admin: []string{"AUTH_alice", "AUTH_bob"}, ReadWrite: []string{"team:dev"}})
+	if s := acl.String(); s != `{"admin":["AUTH_alice","AUTH_bob"],"read-write":["team:dev"],"read-only":["*"]}` {
+		t.Errorf("Expected error: unexpected merge %s", s)
+	}
+	acl = acl.Remove(AccountACL{Admin: []string{"AUTH_alice"}, ReadOnly: []string{"*"}})
+	if s := acl.String(); s != `{"admin":["AUTH_bob"],"read-write":["team:dev"]}` {
+		t.Errorf("Expected error: unexpected remove %s", s)
+	}
+}
+
+func TestParseAccountACLWithInvalidJSON(t *testing.T) {
+	if _, err := ParseAccountACL("admin"); err == nil {
+		t.Errorf("Expected error: %s", "Invalid ACL was accepted.")
+	}
+}
+
+func TestSetAccountACLWithEmptyACL(t *testing.T) {
+	metadata := NewMetadata()
+	metadata.SetAccountACL(AccountACL{})
+	if http.Header(metadata).Get("X-Remove-Account-Access-Control") != "x" {
+		t.Errorf("Expected error: unexpected headers %v", metadata)
+	}
+}
@@ -0,0 +1,214 @@
+package goswift
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ACLKind is the kind of a container ACL element.
+type ACLKind int
+
+const (
+	// ACLReferrer is a ".r:<host>" referrer rule.
+	ACLReferrer ACLKind = iota
+	// ACLListings is the ".rlistings" grant.
+	ACLListings
+	// ACLUser is a "<project>:<user>" grant. Either side may be "*".
+	ACLUser
+	// ACLRole is a bare Keystone role name.
+	ACLRole
+)
+
+// ACLGrant is one comma separated element of X-Container-Read or
+// X-Container-Write.
+type ACLGrant struct {
+	Kind ACLKind
+	// Referrer is the host of an ACLReferrer, "*" for any referrer or
+	// ".example.com" for a domain suffix.
+	Referrer string
+	// Deny marks a negated referrer rule (".r:-<host>").
+	Deny    bool
+	Project string
+	User    string
+	Role    string
+}
+
+func ReferrerGrant(host string) ACLGrant {
+	return ACLGrant{Kind: ACLReferrer, Referrer: host}
+}
+
+func DenyReferrerGrant(host string) ACLGrant {
+	return ACLGrant{Kind: ACLReferrer, Referrer: host, Deny: true}
+}
+
+func ListingsGrant() ACLGrant {
+	return ACLGrant{Kind: ACLListings}
+}
+
+func UserGrant(project, user string) ACLGrant {
+	return ACLGrant{Kind: ACLUser, Project: project, User: user}
+}
+
+func RoleGrant(role string) ACLGrant {
+	return ACLGrant{Kind: ACLRole, Role: role}
+}
+
+func (g ACLGrant) String() string {
+	switch g.Kind {
+	case ACLReferrer:
+		if g.Deny {
+			return ".r:-" + g.Referrer
+		}
+		return ".r:" + g.Referrer
+	case ACLListings:
+		return ".rlistings"
+	case ACLUser:
+		return g.Project + ":" + g.User
+	default:
+		return g.Role
+	}
+}
+
+// ACL is a parsed X-Container-Read or X-Container-Write value.
+type ACL []ACLGrant
+
+// PublicReadACL lets anyone read objects and list the container.
+var PublicReadACL = ACL{ReferrerGrant("*"), ListingsGrant()}
+
+// ParseACL parses a container ACL the way Swift's referrer and user rules
+// are written. Unknown dotted elements are kept as roles so rendering the
+// result round-trips.
+func ParseACL(s string) ACL {
+	var acl ACL
+	for _, e := range strings.Split(s, ",") {
+		e = strings.TrimSpace(e)
+		switch {
+		case e == "":
+		case e == ".rlistings":
+			acl = append(acl, ListingsGrant())
+		case strings.HasPrefix(e, ".r:") || strings.HasPrefix(e, ".ref:") ||
+			strings.HasPrefix(e, ".referer:") || strings.HasPrefix(e, ".referrer:"):
+			host := e[strings.Index(e, ":")+1:]
+			if strings.HasPrefix(host, "-") {
+				acl = append(acl, DenyReferrerGrant(host[1:]))
+			} else {
+				acl = append(acl, ReferrerGrant(host))
+			}
+		case strings.Contains(e, ":"):
+			i := strings.Index(e, ":")
+			acl = append(acl, UserGrant(e[:i], e[i+1:]))
+		default:
+			acl = append(acl, RoleGrant(e))
+		}
+	}
+	return acl
+}
+
+func (a ACL) String() string {
+	items := make([]string, len(a))
+	for i := range a {
+		items[i] = a[i].String()
+	}
+	return strings.Join(items, ",")
+}
+
+// Contains reports whether g is in a.
+func (a ACL) Contains(g ACLGrant) bool {
+	s := g.String()
+	for i := range a {
+		if a[i].String() == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Grant returns a with the grants that are not already present appended.
+func (a ACL) Grant(grants ...ACLGrant) ACL {
+	acl := append(ACL(nil), a...)
+	for _, g := range grants {
+		if !acl.Contains(g) {
+			acl = append(acl, g)
+		}
+	}
+	return acl
+}
+
+// Revoke returns a without grants.
+func (a ACL) Revoke(grants ...ACLGrant) ACL {
+	var acl ACL
+	for i := range a {
+		if !ACL(grants).Contains(a[i]) {
+			acl = append(acl, a[i])
+		}
+	}
+	return acl
+}
+
+// ContainerACL holds the read and write ACLs of a container.
+type ContainerACL struct {
+	Read  ACL
+	Write ACL
+}
+
+// SetACL adds the headers that replace the container read and write ACLs.
+// Empty ACLs are removed.
+func (m *Metadata) SetACL(acl ContainerACL) {
+	setACLHeader(m, "X-Container-Read", acl.Read)
+	setACLHeader(m, "X-Container-Write", acl.Write)
+}
+
+func setACLHeader(m *Metadata, key string, acl ACL) {
+	if len(acl) == 0 {
+		m.SetDeleteMeta(key)
+	} else {
+		m.SetMeta(key, acl.String())
+	}
+}
+
+func (c *Client) GetContainerACL(containerName string) (ContainerACL, error) {
+	header, err := c.ShowContainerMeta(containerName)
+	if err != nil {
+		return ContainerACL{}, err
+	}
+	h := http.Header(header)
+	return ContainerACL{
+		Read:  ParseACL(h.Get("X-Container-Read")),
+		Write: ParseACL(h.Get("X-Container-Write")),
+	}, nil
+}
+
+// SetContainerACL replaces both ACLs of the container.
+func (c *Client) SetContainerACL(containerName string, acl ContainerACL) (http.Header, error) {
+	metadata := NewMetadata()
+	metadata.SetACL(acl)
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+func (c *Client) GrantContainerRead(containerName string, grants ...ACLGrant) (http.Header, error) {
+	return c.updateContainerACL(containerName, "X-Container-Read", func(acl ACL) ACL { return acl.Grant(grants...) })
+}
+
+func (c *Client) RevokeContainerRead(containerName string, grants ...ACLGrant) (http.Header, error) {
+	return c.updateContainerACL(containerName, "X-Container-Read", func(acl ACL) ACL { return acl.Revoke(grants...) })
+}
+
+func (c *Client) GrantContainerWrite(containerName string, grants ...ACLGrant) (http.Header, error) {
+	return c.updateContainerACL(containerName, "X-Container-Write", func(acl ACL) ACL { return acl.Grant(grants...) })
+}
+
+func (c *Client) RevokeContainerWrite(containerName string, grants ...ACLGrant) (http.Header, error) {
+	return c.updateContainerACL(containerName, "X-Container-Write", func(acl ACL) ACL { return acl.Revoke(grants...) })
+}
+
+// updateContainerACL rewrites a single ACL header so the other one is left
+// untouched.
+func (c *Client) updateContainerACL(containerName string, key string, update func(ACL) ACL) (http.Header, error) {
+	header, err := c.ShowContainerMeta(containerName)
+	if err != nil {
+		return nil, err
+	}
+	metadata := NewMetadata()
+	setACLHeader(&metadata, key, update(ParseACL(http.Header(header).Get(key))))
+	return c.CreateContainerMeta(containerName, metadata)
+}
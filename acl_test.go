@@ -0,0 +1,53 @@
+package goswift
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseACL(t *testing.T) {
+	acl := ParseACL(".r:*, .rlistings,.r:-.bad.com,.referrer:good.com,tenant:user,*:admin,swiftoperator")
+	expected := ACL{
+		ReferrerGrant("*"),
+		ListingsGrant(),
+		DenyReferrerGrant(".bad.com"),
+		ReferrerGrant("good.com"),
+		UserGrant("tenant", "user"),
+		UserGrant("*", "admin"),
+		RoleGrant("swiftoperator"),
+	}
+	if len(acl) != len(expected) {
+		t.Fatalf("Expected error: got %v", acl)
+	}
+	for i := range acl {
+		if acl[i] != expected[i] {
+			t.Errorf("Expected error: got %+v, want %+v", acl[i], expected[i])
+		}
+	}
+	if s := acl.String(); s != ".r:*,.rlistings,.r:-.bad.com,.r:good.com,tenant:user,*:admin,swiftoperator" {
+		t.Errorf("Expected error: unexpected rendering %s", s)
+	}
+}
+
+func TestGrantAndRevokeACL(t *testing.T) {
+	acl := PublicReadACL.Grant(ListingsGrant(), UserGrant("p", "u"))
+	if acl.String() != ".r:*,.rlistings,p:u" {
+		t.Errorf("Expected error: unexpected grant %s", acl)
+	}
+	acl = acl.Revoke(ReferrerGrant("*"), ListingsGrant())
+	if acl.String() != "p:u" {
+		t.Errorf("Expected error: unexpected revoke %s", acl)
+	}
+	if PublicReadACL.String() != ".r:*,.rlistings" {
+		t.Errorf("Expected error: %s", "PublicReadACL was modified.")
+	}
+}
+
+func TestSetACL(t *testing.T) {
+	metadata := NewMetadata()
+	metadata.SetACL(ContainerACL{Read: PublicReadACL})
+	h := http.Header(metadata)
+	if h.Get("X-Container-Read") != ".r:*,.rlistings" || h.Get("X-Remove-Container-Write") != "x" {
+		t.Errorf("Expected error: unexpected headers %v", h)
+	}
+}
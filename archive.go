@@ -0,0 +1,137 @@
+package goswift
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// ArchiveFormat is the value of ?extract-archive.
+type ArchiveFormat string
+
+const (
+	ArchiveTar    ArchiveFormat = "tar"
+	ArchiveTarGz  ArchiveFormat = "tar.gz"
+	ArchiveTarBz2 ArchiveFormat = "tar.bz2"
+)
+
+// ExtractArchiveResult is the report of an ?extract-archive upload.
+type ExtractArchiveResult struct {
+	NumberFilesCreated int         `json:"Number Files Created"`
+	ResponseStatus     string      `json:"Response Status"`
+	ResponseBody       string      `json:"Response Body"`
+	Errors             []BulkError `json:"-"`
+}
+
+func (r *ExtractArchiveResult) UnmarshalJSON(data []byte) error {
+	type extractArchiveResult ExtractArchiveResult
+	v := struct {
+		*extractArchiveResult
+		Errors bulkErrors `json:"Errors"`
+	}{extractArchiveResult: (*extractArchiveResult)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.Errors = v.Errors
+	return nil
+}
+
+// ExtractArchive streams archive to uploadPath, where the bulk middleware
+// unpacks it into one object per file. uploadPath is "" to create a
+// container per top level directory, "container" or "container/prefix".
+// metadata is applied to every extracted object. Per-file failures are
+// reported in the result's Errors rather than as an error.
+func (c *Client) ExtractArchive(uploadPath string, format ArchiveFormat, archive io.Reader, metadata Metadata) (*ExtractArchiveResult, error) {
+	header := make(http.Header)
+	for k, v := range metadata {
+		header[k] = v
+	}
+	header.Set("Accept", "application/json")
+	params := make(url.Values)
+	params.Set("extract-archive", string(format))
+	resbody, _, err := c.request("PUT", uploadPath, archive, -1, header, params)
+	if err != nil {
+		return nil, err
+	}
+	result := new(ExtractArchiveResult)
+	if err := json.Unmarshal(resbody, result); err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid extract archive response: %s", err))
+	}
+	return result, bulkStatusError(result.ResponseStatus, result.ResponseBody, len(result.Errors), resbody)
+}
+
+// UploadDirArchive uploads the files below dir to uploadPath as a single
+// archive built on the fly.
+func (c *Client) UploadDirArchive(uploadPath string, dir string, format ArchiveFormat, metadata Metadata) (*ExtractArchiveResult, error) {
+	return c.UploadFSArchive(uploadPath, os.DirFS(dir), format, metadata)
+}
+
+// UploadFSArchive uploads the files of fsys to uploadPath as a single
+// archive built on the fly. Only ArchiveTar and ArchiveTarGz can be built.
+func (c *Client) UploadFSArchive(uploadPath string, fsys fs.FS, format ArchiveFormat, metadata Metadata) (*ExtractArchiveResult, error) {
+	if format != ArchiveTar && format != ArchiveTarGz {
+		return nil, fmt.Errorf("Cannot build %s archives.", format)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(WriteArchive(pw, fsys, format))
+	}()
+	result, err := c.ExtractArchive(uploadPath, format, pr, metadata)
+	pr.CloseWithError(errors.New("Archive upload finished."))
+	return result, err
+}
+
+// WriteArchive writes the regular files of fsys to w as a tar archive,
+// gzipped for ArchiveTarGz.
+func WriteArchive(w io.Writer, fsys fs.FS, format ArchiveFormat) error {
+	switch format {
+	case ArchiveTar:
+		return writeTar(w, fsys)
+	case ArchiveTarGz:
+		gw := gzip.NewWriter(w)
+		if err := writeTar(gw, fsys); err != nil {
+			return err
+		}
+		return gw.Close()
+	}
+	return fmt.Errorf("Cannot build %s archives.", format)
+}
+
+func writeTar(w io.Writer, fsys fs.FS) error {
+	tw := tar.NewWriter(w)
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = path
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -0,0 +1,54 @@
+package goswift
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"testing/fstest"
+)
+
+func TestWriteArchive(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":     {Data: []byte("<html></html>")},
+		"css/style.css":  {Data: []byte("body {}")},
+		"img/empty.json": {Data: []byte("")},
+	}
+	var buf bytes.Buffer
+	if err := WriteArchive(&buf, fsys, ArchiveTarGz); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	gr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	tr := tar.NewReader(gr)
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		b, _ := ioutil.ReadAll(tr)
+		files[hdr.Name] = string(b)
+	}
+	if len(files) != 3 || files["css/style.css"] != "body {}" || files["index.html"] != "<html></html>" {
+		t.Errorf("Expected error: unexpected archive %v", files)
+	}
+	if err := WriteArchive(&buf, fsys, ArchiveTarBz2); err == nil {
+		t.Errorf("Expected error: %s", "bzip2 archive was built.")
+	}
+}
+
+func TestParseExtractArchiveResult(t *testing.T) {
+	body := []byte(`{"Response Status": "201 Created", "Response Body": "", "Errors": [["bb94ba2/big.iso", "413 Request Entity Too Large"]], "Number Files Created": 10}`)
+	var result ExtractArchiveResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if result.NumberFilesCreated != 10 || len(result.Errors) != 1 || result.Errors[0].StatusCode() != 413 {
+		t.Errorf("Expected error: unexpected result %+v", result)
+	}
+}
@@ -0,0 +1,201 @@
+package goswift
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const defaultMaxDeletesPerRequest = 10000
+
+// BulkError is a per-object failure reported by the bulk middleware.
+type BulkError struct {
+	Name   string
+	Status string
+}
+
+// StatusCode returns the HTTP status code of the failure.
+func (e BulkError) StatusCode() int {
+	return statusCode(e.Status)
+}
+
+func statusCode(status string) int {
+	code, _ := strconv.Atoi(strings.SplitN(status, " ", 2)[0])
+	return code
+}
+
+type bulkErrors []BulkError
+
+func (b *bulkErrors) UnmarshalJSON(data []byte) error {
+	var pairs [][]string
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return err
+	}
+	*b = make(bulkErrors, 0, len(pairs))
+	for _, p := range pairs {
+		if len(p) == 2 {
+			*b = append(*b, BulkError{Name: p[0], Status: p[1]})
+		}
+	}
+	return nil
+}
+
+// BulkDeleteResult is the report of a ?bulk-delete request.
+type BulkDeleteResult struct {
+	NumberDeleted  int         `json:"Number Deleted"`
+	NumberNotFound int         `json:"Number Not Found"`
+	ResponseStatus string      `json:"Response Status"`
+	ResponseBody   string      `json:"Response Body"`
+	Errors         []BulkError `json:"-"`
+}
+
+func (r *BulkDeleteResult) UnmarshalJSON(data []byte) error {
+	type bulkDeleteResult BulkDeleteResult
+	v := struct {
+		*bulkDeleteResult
+		Errors bulkErrors `json:"Errors"`
+	}{bulkDeleteResult: (*bulkDeleteResult)(r)}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.Errors = v.Errors
+	return nil
+}
+
+func (r *BulkDeleteResult) add(other *BulkDeleteResult) {
+	r.NumberDeleted += other.NumberDeleted
+	r.NumberNotFound += other.NumberNotFound
+	r.ResponseStatus = other.ResponseStatus
+	r.ResponseBody = other.ResponseBody
+	r.Errors = append(r.Errors, other.Errors...)
+}
+
+func (c *Client) maxDeletesPerRequest() int {
+	if b := c.capabilities().BulkDelete; b != nil && b.MaxDeletesPerRequest > 0 {
+		return b.MaxDeletesPerRequest
+	}
+	return defaultMaxDeletesPerRequest
+}
+
+// supportsBulkDelete is false only when the cluster publishes /info without
+// the bulk_delete section.
+func (c *Client) supportsBulkDelete() bool {
+	caps := c.capabilities()
+	return len(caps.Raw) == 0 || caps.BulkDelete != nil
+}
+
+// BulkDelete deletes objects of containerName with the bulk middleware.
+func (c *Client) BulkDelete(containerName string, objectNames []string) (*BulkDeleteResult, error) {
+	paths := make([]string, len(objectNames))
+	for i := range objectNames {
+		paths[i] = fmt.Sprintf("%s/%s", containerName, objectNames[i])
+	}
+	return c.BulkDeletePaths(paths)
+}
+
+// BulkDeletePaths deletes "container/object" paths, or empty containers
+// given as "container", with the bulk middleware. Paths are sent in batches
+// of the cluster's max_deletes_per_request. Per-object failures are reported
+// in the result's Errors rather than as an error.
+func (c *Client) BulkDeletePaths(paths []string) (*BulkDeleteResult, error) {
+	if !c.supportsBulkDelete() {
+		return nil, errors.New("Cluster does not support bulk delete.")
+	}
+	result := new(BulkDeleteResult)
+	batch := c.maxDeletesPerRequest()
+	for start := 0; start < len(paths); start += batch {
+		end := start + batch
+		if end > len(paths) {
+			end = len(paths)
+		}
+		r, err := c.bulkDelete(paths[start:end])
+		if err != nil {
+			return result, err
+		}
+		result.add(r)
+	}
+	return result, nil
+}
+
+func (c *Client) bulkDelete(paths []string) (*BulkDeleteResult, error) {
+	var body strings.Builder
+	for _, p := range paths {
+		body.WriteString((&url.URL{Path: "/" + strings.TrimPrefix(p, "/")}).EscapedPath())
+		body.WriteString("\n")
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", "text/plain")
+	header.Set("Accept", "application/json")
+	params := make(url.Values)
+	params.Set("bulk-delete", "")
+	resbody, _, err := c.request("POST", "", strings.NewReader(body.String()), int64(body.Len()), header, params)
+	if err != nil {
+		return nil, err
+	}
+	result := new(BulkDeleteResult)
+	if err := json.Unmarshal(resbody, result); err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid bulk delete response: %s", err))
+	}
+	return result, bulkStatusError(result.ResponseStatus, result.ResponseBody, len(result.Errors), resbody)
+}
+
+// bulkStatusError turns a failed Response Status without per-object errors,
+// i.e. a rejected request, into an *Error.
+func bulkStatusError(status string, message string, errorCount int, resbody []byte) error {
+	if code := statusCode(status); errorCount == 0 && (code < 200 || code > 299) {
+		return &Error{Code: code, Message: message, Body: string(resbody)}
+	}
+	return nil
+}
+
+// DeleteContainerRecursive deletes every object of containerName and then
+// the container itself. Objects are removed a listing page at a time with
+// the bulk middleware, or one by one if the cluster does not support it.
+func (c *Client) DeleteContainerRecursive(containerName string) (*BulkDeleteResult, error) {
+	result := new(BulkDeleteResult)
+	bulk := c.supportsBulkDelete()
+	marker := ""
+	for {
+		objects, _, err := c.ListObjectsWithParams(containerName, Params{Marker: marker})
+		if err != nil {
+			return result, err
+		}
+		if len(objects) == 0 {
+			break
+		}
+		names := make([]string, len(objects))
+		for i := range objects {
+			names[i] = objects[i].Name
+		}
+		marker = names[len(names)-1]
+		if bulk {
+			r, err := c.BulkDelete(containerName, names)
+			if r != nil {
+				result.add(r)
+			}
+			if err != nil {
+				return result, err
+			}
+		} else {
+			for _, name := range names {
+				if err := c.DeleteObject(containerName, name); err != nil {
+					if !isNotFound(err) {
+						return result, err
+					}
+					result.NumberNotFound++
+				} else {
+					result.NumberDeleted++
+				}
+			}
+		}
+		if len(result.Errors) != 0 {
+			return result, errors.New(fmt.Sprintf("Could not delete %d objects of %s.", len(result.Errors), containerName))
+		}
+	}
+	_, err := c.DeleteContainer(containerName)
+	return result, err
+}
@@ -0,0 +1,89 @@
+package goswift
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseBulkDeleteResult(t *testing.T) {
+	body := []byte(`
+{"Number Not Found": 1, "Response Status": "400 Bad Request", "Errors": [["/bb94ba2/aa94ba2.json", "409 Conflict"]], "Number Deleted": 2, "Response Body": ""}`)
+	var result BulkDeleteResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if result.NumberDeleted != 2 || result.NumberNotFound != 1 || result.ResponseStatus != "400 Bad Request" {
+		t.Errorf("Expected error: unexpected result %+v", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Name != "/bb94ba2/aa94ba2.json" || result.Errors[0].StatusCode() != 409 {
+		t.Errorf("Expected error: unexpected errors %+v", result.Errors)
+	}
+}
+
+func TestBulkDeleteBatches(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.info = `{"bulk_delete": {"max_deletes_per_request": 2}}`
+	for _, name := range []string{"a b", "c%d", "e/f", "g"} {
+		s.putObject("c", name, []byte(name), nil)
+	}
+	result, err := c.BulkDelete("c", []string{"a b", "c%d", "e/f", "g", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NumberDeleted != 4 || result.NumberNotFound != 1 || len(result.Errors) != 0 {
+		t.Errorf("Expected error: unexpected result %+v", result)
+	}
+	posts := s.recorded("POST")
+	if len(posts) != 3 {
+		t.Fatalf("Expected error: %d requests, expected 3", len(posts))
+	}
+	if body := string(posts[0].Body); body != "/c/a%20b\n/c/c%25d\n" {
+		t.Errorf("Expected error: unexpected body %q", body)
+	}
+	if posts[0].Header.Get("Content-Type") != "text/plain" || !posts[0].Query.Has("bulk-delete") {
+		t.Errorf("Expected error: unexpected request %+v", posts[0])
+	}
+	if names := s.objectNames("c"); len(names) != 0 {
+		t.Errorf("Expected error: objects were left: %v", names)
+	}
+}
+
+func TestDeleteContainerRecursive(t *testing.T) {
+	for _, test := range []struct {
+		info    string
+		posts   int
+		deletes int
+	}{
+		{`{"bulk_delete": {"max_deletes_per_request": 10}}`, 3, 1},
+		// one by one without bulk delete
+		{`{"swift": {}}`, 0, 6},
+	} {
+		s, c := newFakeSwift(t)
+		s.info = test.info
+		s.listingLimit = 2
+		for _, name := range []string{"1", "2", "3", "4", "5"} {
+			s.putObject("c", name, []byte(name), nil)
+		}
+		result, err := c.DeleteContainerRecursive("c")
+		if err != nil {
+			t.Fatalf("Expected error: %s", err)
+		}
+		if result.NumberDeleted != 5 {
+			t.Errorf("Expected error: unexpected result %+v", result)
+		}
+		if _, err := c.ShowContainerMeta("c"); !isNotFound(err) {
+			t.Errorf("Expected error: container was not deleted: %v", err)
+		}
+		if posts, deletes := len(s.recorded("POST")), len(s.recorded("DELETE")); posts != test.posts || deletes != test.deletes {
+			t.Errorf("Expected error: %d bulk requests and %d deletes, expected %d and %d", posts, deletes, test.posts, test.deletes)
+		}
+	}
+}
+
+func TestBulkDeleteUnsupported(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.info = `{"swift": {}}`
+	if _, err := c.BulkDeletePaths([]string{"c/a"}); err == nil {
+		t.Errorf("Expected error: %s", "Bulk delete was sent to a cluster without it.")
+	}
+}
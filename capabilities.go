@@ -0,0 +1,242 @@
+package goswift
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Capabilities is the parsed body of the cluster's /info endpoint.
+// Middleware that is not enabled in the cluster has a nil section.
+type Capabilities struct {
+	Swift      SwiftInfo       `json:"swift"`
+	SLO        *SLOInfo        `json:"slo"`
+	TempURL    *TempURLInfo    `json:"tempurl"`
+	BulkDelete *BulkDeleteInfo `json:"bulk_delete"`
+	BulkUpload *BulkUploadInfo `json:"bulk_upload"`
+	Symlink    *SymlinkInfo    `json:"symlink"`
+	// Raw holds every section by name, including those without a typed
+	// field above.
+	Raw map[string]json.RawMessage `json:"-"`
+	// Admin holds the sections that are only returned to signed requests.
+	Admin map[string]json.RawMessage `json:"admin"`
+}
+
+type SwiftInfo struct {
+	Version                string       `json:"version"`
+	MaxFileSize            int64        `json:"max_file_size"`
+	MaxMetaNameLength      int          `json:"max_meta_name_length"`
+	MaxMetaValueLength     int          `json:"max_meta_value_length"`
+	MaxMetaCount           int          `json:"max_meta_count"`
+	MaxMetaOverallSize     int          `json:"max_meta_overall_size"`
+	MaxHeaderSize          int          `json:"max_header_size"`
+	MaxObjectNameLength    int          `json:"max_object_name_length"`
+	MaxAccountNameLength   int          `json:"max_account_name_length"`
+	MaxContainerNameLength int          `json:"max_container_name_length"`
+	ContainerListingLimit  int          `json:"container_listing_limit"`
+	AccountListingLimit    int          `json:"account_listing_limit"`
+	StrictCorsMode         bool         `json:"strict_cors_mode"`
+	Policies               []PolicyInfo `json:"policies"`
+}
+
+type PolicyInfo struct {
+	Name    string `json:"name"`
+	Aliases string `json:"aliases"`
+	Default bool   `json:"default"`
+}
+
+type SLOInfo struct {
+	MaxManifestSegments int   `json:"max_manifest_segments"`
+	MaxManifestSize     int64 `json:"max_manifest_size"`
+	MinSegmentSize      int64 `json:"min_segment_size"`
+}
+
+type TempURLInfo struct {
+	Methods               []string `json:"methods"`
+	AllowedDigests        []string `json:"allowed_digests"`
+	IncomingAllowHeaders  []string `json:"incoming_allow_headers"`
+	IncomingRemoveHeaders []string `json:"incoming_remove_headers"`
+	OutgoingAllowHeaders  []string `json:"outgoing_allow_headers"`
+	OutgoingRemoveHeaders []string `json:"outgoing_remove_headers"`
+}
+
+type BulkDeleteInfo struct {
+	MaxDeletesPerRequest int `json:"max_deletes_per_request"`
+	MaxFailedDeletes     int `json:"max_failed_deletes"`
+}
+
+type BulkUploadInfo struct {
+	MaxContainersPerExtraction int `json:"max_containers_per_extraction"`
+	MaxFailedExtractions       int `json:"max_failed_extractions"`
+}
+
+type SymlinkInfo struct {
+	SymloopMax  int  `json:"symloop_max"`
+	StaticLinks bool `json:"static_links"`
+}
+
+// Swift's defaults, used when the cluster does not publish /info.
+const (
+	defaultMaxFileSize         = 5368709122
+	defaultMaxManifestSegments = 1000
+)
+
+func (caps *Capabilities) UnmarshalJSON(b []byte) error {
+	type capabilities Capabilities
+	var v capabilities
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &v.Raw); err != nil {
+		return err
+	}
+	*caps = Capabilities(v)
+	return nil
+}
+
+// Has reports whether the cluster advertises the named section, e.g.
+// "staticweb" or "container_sync".
+func (caps *Capabilities) Has(name string) bool {
+	_, ok := caps.Raw[name]
+	return ok
+}
+
+// Section decodes the named section into v.
+func (caps *Capabilities) Section(name string, v interface{}) error {
+	raw, ok := caps.Raw[name]
+	if !ok {
+		return fmt.Errorf("Cluster does not support %s", name)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// MetadataLimits returns the cluster's user metadata limits, falling back to
+// DefaultMetadataLimits for the ones it does not publish.
+func (caps *Capabilities) MetadataLimits() MetadataLimits {
+	limits := DefaultMetadataLimits
+	if caps.Swift.MaxMetaNameLength > 0 {
+		limits.MaxNameLength = caps.Swift.MaxMetaNameLength
+	}
+	if caps.Swift.MaxMetaValueLength > 0 {
+		limits.MaxValueLength = caps.Swift.MaxMetaValueLength
+	}
+	if caps.Swift.MaxMetaCount > 0 {
+		limits.MaxCount = caps.Swift.MaxMetaCount
+	}
+	if caps.Swift.MaxMetaOverallSize > 0 {
+		limits.MaxOverallSize = caps.Swift.MaxMetaOverallSize
+	}
+	return limits
+}
+
+// infoUrl derives the /info URL from the storage URL, which is the same
+// host with everything from the /v1 path segment dropped.
+func (c *Client) infoUrl() (string, error) {
+	u, err := url.Parse(c.StorageUrl)
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimRight(u.Path, "/")
+	if i := strings.Index(path+"/", "/v1/"); i >= 0 {
+		path = path[:i]
+	} else if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[:i]
+	}
+	u.Path = path + "/info"
+	u.RawQuery = ""
+	return u.String(), nil
+}
+
+// GetCapabilities fetches the cluster's /info and caches it in
+// c.Capabilities.
+func (c *Client) GetCapabilities() (*Capabilities, error) {
+	caps, err := c.getCapabilities(nil)
+	if err != nil {
+		return nil, err
+	}
+	capabilitiesMu.Lock()
+	c.Capabilities = caps
+	capabilitiesMu.Unlock()
+	return caps, nil
+}
+
+// GetAdminCapabilities fetches /info signed with the cluster's admin_key so
+// the response also carries the admin sections. The signature is valid
+// until expires.
+func (c *Client) GetAdminCapabilities(adminKey string, expires time.Time) (*Capabilities, error) {
+	params := make(url.Values)
+	params.Set("swiftinfo_sig", InfoSignature(adminKey, "GET", expires))
+	params.Set("swiftinfo_expires", fmt.Sprintf("%d", expires.Unix()))
+	return c.getCapabilities(params)
+}
+
+// InfoSignature returns the swiftinfo_sig of an admin /info request.
+func InfoSignature(adminKey string, method string, expires time.Time) string {
+	mac := hmac.New(sha1.New, []byte(adminKey))
+	fmt.Fprintf(mac, "%s\n%d\n%s", method, expires.Unix(), "/info")
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Client) getCapabilities(params url.Values) (*Capabilities, error) {
+	c.setClient()
+	if err := c.setCredential(); err != nil {
+		return nil, err
+	}
+	urls, err := c.infoUrl()
+	if err != nil {
+		return nil, err
+	}
+	if params != nil {
+		urls += "?" + params.Encode()
+	}
+	res, err := c.do("GET", urls, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	caps := new(Capabilities)
+	if err := json.Unmarshal(body, caps); err != nil {
+		return nil, errors.New(fmt.Sprintf("Invalid /info response: %s", err))
+	}
+	return caps, nil
+}
+
+// capabilitiesMu guards Client.Capabilities, which is filled in lazily
+// while workers of a bulk operation may be reading it.
+var capabilitiesMu sync.Mutex
+
+// capabilities returns the cached capabilities, fetching them once. When
+// /info cannot be read, e.g. because the cluster does not publish it or
+// answers 403 with expose_info disabled, Swift's defaults apply. Only a 404
+// is cached; after other errors /info is tried again on the next call.
+func (c *Client) capabilities() *Capabilities {
+	capabilitiesMu.Lock()
+	caps := c.Capabilities
+	capabilitiesMu.Unlock()
+	if caps != nil {
+		return caps
+	}
+	caps, err := c.getCapabilities(nil)
+	if err != nil {
+		if !isNotFound(err) {
+			return new(Capabilities)
+		}
+		caps = new(Capabilities)
+	}
+	capabilitiesMu.Lock()
+	c.Capabilities = caps
+	capabilitiesMu.Unlock()
+	return caps
+}
@@ -0,0 +1,155 @@
+package goswift
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+var infoJson = []byte(`{
+"swift": {"version": "2.23.0", "max_file_size": 5368709122, "max_meta_count": 45,
+  "policies": [{"name": "gold", "default": true}, {"name": "ec", "aliases": "ec, cold"}]},
+"slo": {"max_manifest_segments": 1000, "max_manifest_size": 8388608, "min_segment_size": 1048576},
+"bulk_delete": {"max_deletes_per_request": 10000, "max_failed_deletes": 1000},
+"tempurl": {"methods": ["GET", "HEAD", "PUT"], "allowed_digests": ["sha1", "sha256"]},
+"staticweb": {}
+}`)
+
+func TestParseCapabilities(t *testing.T) {
+	var caps Capabilities
+	if err := json.Unmarshal(infoJson, &caps); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if caps.Swift.Version != "2.23.0" || len(caps.Swift.Policies) != 2 || !caps.Swift.Policies[0].Default {
+		t.Errorf("Expected error: unexpected swift section %+v", caps.Swift)
+	}
+	if caps.SLO == nil || caps.SLO.MinSegmentSize != 1048576 {
+		t.Errorf("Expected error: unexpected slo section %+v", caps.SLO)
+	}
+	if caps.BulkDelete == nil || caps.BulkDelete.MaxDeletesPerRequest != 10000 {
+		t.Errorf("Expected error: unexpected bulk_delete section %+v", caps.BulkDelete)
+	}
+	if caps.Symlink != nil {
+		t.Errorf("Expected error: %s", "Missing section was not nil.")
+	}
+	if !caps.Has("staticweb") || caps.Has("container_sync") {
+		t.Errorf("Expected error: unexpected sections %v", caps.Raw)
+	}
+	if caps.MetadataLimits().MaxCount != 45 || caps.MetadataLimits().MaxNameLength != 128 {
+		t.Errorf("Expected error: unexpected limits %+v", caps.MetadataLimits())
+	}
+}
+
+func TestInfoUrl(t *testing.T) {
+	for storageUrl, expected := range map[string]string{
+		"https://swift.example.com/v1/AUTH_test":        "https://swift.example.com/info",
+		"https://swift.example.com:8080/v1/AUTH_test/":  "https://swift.example.com:8080/info",
+		"https://example.com/object-store/v1/AUTH_test": "https://example.com/object-store/info",
+	} {
+		c := Client{StorageUrl: storageUrl}
+		if u, err := c.infoUrl(); err != nil || u != expected {
+			t.Errorf("Expected error: got %s for %s, want %s", u, storageUrl, expected)
+		}
+	}
+}
+
+func TestInfoSignature(t *testing.T) {
+	sig := InfoSignature("secret_admin_key", "GET", time.Unix(1400000000, 0))
+	if sig != "60fcc52eb08d6e494518b95dfa4fdc09d7eedee2" {
+		t.Errorf("Expected error: unexpected signature %s", sig)
+	}
+}
+
+func TestSegmentSize(t *testing.T) {
+	caps := &Capabilities{Swift: SwiftInfo{MaxFileSize: 1 << 30}, SLO: &SLOInfo{MaxManifestSegments: 10, MinSegmentSize: 1 << 20}}
+	c := Client{ChunkSize: 1024, Capabilities: caps}
+	if seg, err := c.segmentSize(4 << 20); err != nil || seg != 1<<20 {
+		t.Errorf("Expected error: min_segment_size was not applied: %d %v", seg, err)
+	}
+	if seg, err := c.segmentSize(100 << 20); err != nil || seg != 10<<20 {
+		t.Errorf("Expected error: max_manifest_segments was not applied: %d %v", seg, err)
+	}
+	if _, err := c.segmentSize(100 << 30); err == nil {
+		t.Errorf("Expected error: %s", "Oversized object was accepted.")
+	}
+	if !c.needsSegments(2048) || c.needsSegments(1024) {
+		t.Errorf("Expected error: %s", "ChunkSize was not honoured.")
+	}
+}
+
+func TestCapabilitiesUnavailable(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("test", "placeholder", nil, nil)
+	s.fail = func(r *http.Request) int {
+		if r.URL.Path == "/info" {
+			return http.StatusForbidden
+		}
+		return 0
+	}
+	if caps := c.capabilities(); caps == nil || caps.SLO != nil || c.Capabilities != nil {
+		t.Errorf("Expected error: %s", "Unreadable /info did not fall back to uncached defaults.")
+	}
+	f, err := ioutil.TempFile("", "goswift-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("0123456789")
+	f.Close()
+	defer os.Remove(f.Name())
+	s.mu.Lock()
+	s.requests = nil
+	s.mu.Unlock()
+	if _, err := c.CreateObject("test", "small", f.Name(), nil); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if gets := s.recorded("GET"); len(gets) != 0 {
+		t.Errorf("Expected error: a single PUT fetched /info: %+v", gets)
+	}
+
+	// segmented uploads use Swift's defaults
+	c.ChunkSize = 4
+	if _, err := c.CreateObject("test", "large", f.Name(), nil); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if o := s.object("test", "large"); o == nil || len(o.manifest) != 3 {
+		t.Errorf("Expected error: %s", "Large object was not uploaded as an SLO.")
+	}
+
+	s.mu.Lock()
+	s.fail = nil
+	s.info = string(infoJson)
+	s.mu.Unlock()
+	if caps := c.capabilities(); caps.SLO == nil || c.Capabilities != caps {
+		t.Errorf("Expected error: %s", "Capabilities were not fetched again.")
+	}
+
+	// a cluster without /info gets Swift's defaults, cached
+	_, c = newFakeSwift(t)
+	if caps := c.capabilities(); caps != c.Capabilities || caps.SLO != nil {
+		t.Errorf("Expected error: %s", "Missing /info was not cached as empty.")
+	}
+}
+
+func TestCreateObjectEmptyFile(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("test", "placeholder", nil, nil)
+	f, err := ioutil.TempFile("", "goswift-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	if _, err := c.CreateObject("test", "empty", f.Name(), NewMetadata()); err != nil {
+		t.Fatal(err)
+	}
+	puts := s.recorded("PUT")
+	if len(puts) != 1 || puts[0].ContentLength != 0 || len(puts[0].TransferEncoding) != 0 {
+		t.Errorf("Expected error: empty file was not sent with Content-Length 0: %+v", puts)
+	}
+	if o := s.object("test", "empty"); o == nil || len(o.data) != 0 {
+		t.Errorf("Expected error: %s", "Empty object was not created.")
+	}
+}
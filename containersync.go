@@ -0,0 +1,134 @@
+package goswift
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SyncTarget is the value of X-Container-Sync-To. Realm style targets
+// ("//realm/cluster/account/container") set Realm and Cluster; legacy
+// targets are a full URL and set Url.
+type SyncTarget struct {
+	Realm     string
+	Cluster   string
+	Url       string
+	Account   string
+	Container string
+}
+
+// RealmSyncTarget returns a realm style target.
+func RealmSyncTarget(realm, cluster, account, container string) SyncTarget {
+	return SyncTarget{Realm: realm, Cluster: cluster, Account: account, Container: container}
+}
+
+// ParseSyncTarget parses a X-Container-Sync-To value.
+func ParseSyncTarget(s string) (SyncTarget, error) {
+	if strings.HasPrefix(s, "//") {
+		parts := strings.Split(strings.TrimPrefix(s, "//"), "/")
+		if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+			return SyncTarget{}, fmt.Errorf("Invalid X-Container-Sync-To %q; expected //realm/cluster/account/container", s)
+		}
+		return RealmSyncTarget(parts[0], parts[1], parts[2], parts[3]), nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return SyncTarget{}, fmt.Errorf("Invalid X-Container-Sync-To %q", s)
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[len(parts)-2] == "" || parts[len(parts)-1] == "" {
+		return SyncTarget{}, fmt.Errorf("Invalid X-Container-Sync-To %q; expected a container URL", s)
+	}
+	return SyncTarget{Url: s, Account: parts[len(parts)-2], Container: parts[len(parts)-1]}, nil
+}
+
+func (t SyncTarget) String() string {
+	if t.Url != "" {
+		return t.Url
+	}
+	return fmt.Sprintf("//%s/%s/%s/%s", t.Realm, t.Cluster, t.Account, t.Container)
+}
+
+// ContainerSync is the container sync configuration and usage of a
+// container. Swift does not report sync progress, so comparing the usage of
+// both sides is the best available status.
+type ContainerSync struct {
+	// To is nil when the container is not synced.
+	To          *SyncTarget
+	Key         string
+	ObjectCount int64
+	BytesUsed   int64
+}
+
+func (c *Client) GetContainerSync(containerName string) (*ContainerSync, error) {
+	header, err := c.ShowContainerMeta(containerName)
+	if err != nil {
+		return nil, err
+	}
+	info, err := ParseContainerInfo(header)
+	if err != nil {
+		return nil, err
+	}
+	h := http.Header(header)
+	status := &ContainerSync{Key: h.Get("X-Container-Sync-Key"), ObjectCount: info.ObjectCount, BytesUsed: info.BytesUsed}
+	if v := h.Get("X-Container-Sync-To"); v != "" {
+		to, err := ParseSyncTarget(v)
+		if err != nil {
+			return nil, err
+		}
+		status.To = &to
+	}
+	return status, nil
+}
+
+// SetContainerSync syncs the container to target, authenticating with the
+// shared key that the target container must also be configured with.
+func (c *Client) SetContainerSync(containerName string, target SyncTarget, key string) (http.Header, error) {
+	if key == "" {
+		return nil, errors.New("Container sync needs a key.")
+	}
+	metadata := NewMetadata()
+	metadata.SetMeta("X-Container-Sync-To", target.String())
+	metadata.SetMeta("X-Container-Sync-Key", key)
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+func (c *Client) ClearContainerSync(containerName string) (http.Header, error) {
+	metadata := NewMetadata()
+	metadata.SetDeleteMeta("X-Container-Sync-To")
+	metadata.SetDeleteMeta("X-Container-Sync-Key")
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+// VerifyContainerSync checks that containerName syncs to remoteContainer of
+// the remote client's account and that the remote container syncs back with
+// the same key. Only the account and container of the targets can be
+// compared; realm and cluster names are resolved by the clusters.
+func (c *Client) VerifyContainerSync(containerName string, remote *Client, remoteContainerName string) error {
+	local, err := c.GetContainerSync(containerName)
+	if err != nil {
+		return err
+	}
+	peer, err := remote.GetContainerSync(remoteContainerName)
+	if err != nil {
+		return err
+	}
+	if local.To == nil {
+		return fmt.Errorf("Container %s is not synced.", containerName)
+	}
+	if peer.To == nil {
+		return fmt.Errorf("Remote container %s is not synced.", remoteContainerName)
+	}
+	if local.To.Account != remote.accountName() || local.To.Container != remoteContainerName {
+		return fmt.Errorf("Container %s syncs to %s, not to %s/%s.", containerName, local.To, remote.accountName(), remoteContainerName)
+	}
+	if peer.To.Account != c.accountName() || peer.To.Container != containerName {
+		return fmt.Errorf("Remote container %s syncs to %s, not to %s/%s.", remoteContainerName, peer.To, c.accountName(), containerName)
+	}
+	if local.Key != peer.Key {
+		return errors.New("Container sync keys differ.")
+	}
+	return nil
+}
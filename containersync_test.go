@@ -0,0 +1,70 @@
+package goswift
+
+import "testing"
+
+func TestParseSyncTarget(t *testing.T) {
+	target, err := ParseSyncTarget("//realm1/cluster1/AUTH_test/backup")
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if target.Realm != "realm1" || target.Cluster != "cluster1" || target.Account != "AUTH_test" || target.Container != "backup" {
+		t.Errorf("Expected error: unexpected target %+v", target)
+	}
+	if target.String() != "//realm1/cluster1/AUTH_test/backup" {
+		t.Errorf("Expected error: unexpected string %s", target)
+	}
+
+	target, err = ParseSyncTarget("https://swift.example.com/v1/AUTH_test/backup")
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if target.Account != "AUTH_test" || target.Container != "backup" || target.String() != "https://swift.example.com/v1/AUTH_test/backup" {
+		t.Errorf("Expected error: unexpected target %+v", target)
+	}
+
+	for _, s := range []string{"//realm/cluster/account", "//realm//account/container", "swift.example.com/v1/a/c", "https://swift.example.com/backup"} {
+		if _, err := ParseSyncTarget(s); err == nil {
+			t.Errorf("Expected error: %q was accepted", s)
+		}
+	}
+}
+
+func TestVerifyContainerSync(t *testing.T) {
+	_, local := newFakeSwift(t)
+	_, remote := newFakeSwift(t)
+	local.CreateContainer("a")
+	remote.CreateContainer("b")
+	if err := local.VerifyContainerSync("a", remote, "b"); err == nil {
+		t.Errorf("Expected error: %s", "Unsynced containers were verified.")
+	}
+	if _, err := local.SetContainerSync("a", RealmSyncTarget("realm", "remote", "AUTH_test", "b"), "secret"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if err := local.VerifyContainerSync("a", remote, "b"); err == nil {
+		t.Errorf("Expected error: %s", "One way sync was verified.")
+	}
+	if _, err := remote.SetContainerSync("b", RealmSyncTarget("realm", "local", "AUTH_test", "c"), "secret"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if err := local.VerifyContainerSync("a", remote, "b"); err == nil {
+		t.Errorf("Expected error: %s", "Sync back to another container was verified.")
+	}
+	if _, err := remote.SetContainerSync("b", RealmSyncTarget("realm", "local", "AUTH_test", "a"), "other"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if err := local.VerifyContainerSync("a", remote, "b"); err == nil {
+		t.Errorf("Expected error: %s", "Mismatched keys were verified.")
+	}
+	if _, err := remote.SetContainerSync("b", RealmSyncTarget("realm", "local", "AUTH_test", "a"), "secret"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if err := local.VerifyContainerSync("a", remote, "b"); err != nil {
+		t.Errorf("Expected error: %s", err)
+	}
+	if _, err := remote.ClearContainerSync("b"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if err := local.VerifyContainerSync("a", remote, "b"); err == nil {
+		t.Errorf("Expected error: %s", "Cleared sync was verified.")
+	}
+}
@@ -0,0 +1,88 @@
+package goswift
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CopyOptions controls a server-side copy.
+type CopyOptions struct {
+	// DestinationAccount copies into another account the token can write
+	// to, e.g. "AUTH_other".
+	DestinationAccount string
+	// FreshMetadata drops the user metadata of the source object.
+	FreshMetadata bool
+	// ContentType overrides the content type of the copy.
+	ContentType string
+	// Metadata is sent with the copy, e.g. to add or override user metadata.
+	Metadata Metadata
+	// ManifestCopy copies a large object's manifest rather than its
+	// content, with ?multipart-manifest=get.
+	ManifestCopy bool
+	// UseCopyFrom issues a PUT with X-Copy-From instead of COPY, for
+	// proxies that block the COPY verb.
+	UseCopyFrom bool
+}
+
+// CopyObjectWithOptions copies an object on the server side.
+func (c *Client) CopyObjectWithOptions(fromContainerName string, fromObjectName string, toContainerName string, toObjectName string, opts CopyOptions) (http.Header, error) {
+	toObjectPath := fmt.Sprintf("%s/%s", toContainerName, toObjectName)
+	fromObjectPath := fmt.Sprintf("%s/%s", fromContainerName, fromObjectName)
+	header := make(http.Header)
+	for k, v := range opts.Metadata {
+		header[k] = v
+	}
+	if opts.FreshMetadata {
+		header.Set("X-Fresh-Metadata", "true")
+	}
+	if opts.ContentType != "" {
+		header.Set("Content-Type", opts.ContentType)
+	}
+	params := make(url.Values)
+	if opts.ManifestCopy {
+		params.Set("multipart-manifest", "get")
+	}
+	if !opts.UseCopyFrom {
+		header.Set("Destination", escapePath(toObjectPath))
+		if opts.DestinationAccount != "" {
+			header.Set("Destination-Account", opts.DestinationAccount)
+		}
+		_, h, err := c.request("COPY", fromObjectPath, nil, 0, header, params)
+		return h, err
+	}
+	header.Set("X-Copy-From", escapePath(fromObjectPath))
+	if opts.DestinationAccount == "" {
+		_, h, err := c.request("PUT", toObjectPath, nil, 0, header, params)
+		return h, err
+	}
+	// A cross-account PUT goes to the destination account and names the
+	// source account instead.
+	c.setClient()
+	if err := c.setCredential(); err != nil {
+		return nil, err
+	}
+	header.Set("X-Copy-From-Account", c.accountName())
+	params.Set("format", "json")
+	urls := fmt.Sprintf("%s/%s?%s", c.accountStorageUrl(opts.DestinationAccount), toObjectPath, params.Encode())
+	res, err := c.do("PUT", urls, nil, 0, header)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	return res.Header, nil
+}
+
+// accountName returns the account of the storage URL, e.g. "AUTH_test".
+func (c *Client) accountName() string {
+	u := strings.TrimRight(c.StorageUrl, "/")
+	return u[strings.LastIndex(u, "/")+1:]
+}
+
+// accountStorageUrl returns the storage URL of another account on the same
+// cluster.
+func (c *Client) accountStorageUrl(account string) string {
+	u := strings.TrimRight(c.StorageUrl, "/")
+	return u[:strings.LastIndex(u, "/")+1] + account
+}
@@ -0,0 +1,73 @@
+package goswift
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAccountStorageUrl(t *testing.T) {
+	c := Client{StorageUrl: "https://swift.example.com/v1/AUTH_test/"}
+	if a := c.accountName(); a != "AUTH_test" {
+		t.Errorf("Expected error: unexpected account %s", a)
+	}
+	if u := c.accountStorageUrl("AUTH_other"); u != "https://swift.example.com/v1/AUTH_other" {
+		t.Errorf("Expected error: unexpected url %s", u)
+	}
+}
+
+func TestCopyObjectWithOptions(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("src", "a b", []byte("data"), http.Header{"X-Object-Meta-Color": {"red"}})
+	s.putObject("dst", "placeholder", nil, nil)
+	s.fail = func(r *http.Request) int {
+		if strings.HasPrefix(r.URL.Path, "/v1/AUTH_other/") {
+			return http.StatusCreated
+		}
+		return 0
+	}
+	last := func() fakeRequest {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.requests[len(s.requests)-1]
+	}
+
+	if _, err := c.CopyObjectWithOptions("src", "a b", "dst", "c d", CopyOptions{FreshMetadata: true, ContentType: "text/plain"}); err != nil {
+		t.Fatal(err)
+	}
+	r := last()
+	if r.Method != "COPY" || r.Path != "/v1/AUTH_test/src/a b" || r.Header.Get("Destination") != "dst/c%20d" ||
+		r.Header.Get("X-Fresh-Metadata") != "true" || r.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("Expected error: unexpected COPY %+v", r)
+	}
+	if o := s.object("dst", "c d"); o == nil || string(o.data) != "data" {
+		t.Errorf("Expected error: %s", "Object was not copied.")
+	}
+
+	if _, err := c.CopyObjectWithOptions("src", "a b", "dst", "e", CopyOptions{DestinationAccount: "AUTH_other", ManifestCopy: true}); err != nil {
+		t.Fatal(err)
+	}
+	r = last()
+	if r.Method != "COPY" || r.Header.Get("Destination-Account") != "AUTH_other" || r.Query.Get("multipart-manifest") != "get" ||
+		r.Header.Get("X-Fresh-Metadata") != "" {
+		t.Errorf("Expected error: unexpected COPY %+v", r)
+	}
+
+	if _, err := c.CopyObjectWithOptions("src", "a b", "dst", "f", CopyOptions{UseCopyFrom: true, ManifestCopy: true}); err != nil {
+		t.Fatal(err)
+	}
+	r = last()
+	if r.Method != "PUT" || r.Path != "/v1/AUTH_test/dst/f" || r.Header.Get("X-Copy-From") != "src/a%20b" ||
+		r.Query.Get("multipart-manifest") != "get" || r.Header.Get("Destination") != "" || r.ContentLength != 0 {
+		t.Errorf("Expected error: unexpected PUT %+v", r)
+	}
+
+	if _, err := c.CopyObjectWithOptions("src", "a b", "dst", "g", CopyOptions{UseCopyFrom: true, DestinationAccount: "AUTH_other"}); err != nil {
+		t.Fatal(err)
+	}
+	r = last()
+	if r.Method != "PUT" || r.Path != "/v1/AUTH_other/dst/g" || r.Header.Get("X-Copy-From") != "src/a%20b" ||
+		r.Header.Get("X-Copy-From-Account") != "AUTH_test" || r.Query.Has("multipart-manifest") {
+		t.Errorf("Expected error: unexpected PUT %+v", r)
+	}
+}
@@ -0,0 +1,153 @@
+package goswift
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// CORSPolicy is the CORS configuration of a container.
+type CORSPolicy struct {
+	// AllowOrigins lists the origins allowed to make requests, e.g.
+	// "https://example.com", or "*" for any origin.
+	AllowOrigins []string
+	// MaxAge is how many seconds browsers may cache a preflight response.
+	// Zero leaves it unset.
+	MaxAge int
+	// ExposeHeaders lists response headers browsers may expose to scripts.
+	ExposeHeaders []string
+}
+
+// Validate checks the policy for values Swift would silently ignore.
+func (p CORSPolicy) Validate() error {
+	for _, o := range p.AllowOrigins {
+		if o == "*" {
+			continue
+		}
+		u, err := url.Parse(o)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return fmt.Errorf("Invalid CORS origin %q; expected scheme://host[:port] or *", o)
+		}
+	}
+	if p.MaxAge < 0 {
+		return fmt.Errorf("Invalid CORS max age %d", p.MaxAge)
+	}
+	for _, h := range p.ExposeHeaders {
+		if h == "" || !validHeaderToken(h) {
+			return fmt.Errorf("Invalid CORS expose header %q", h)
+		}
+	}
+	return nil
+}
+
+func (c *Client) GetContainerCORS(containerName string) (CORSPolicy, error) {
+	header, err := c.ShowContainerMeta(containerName)
+	if err != nil {
+		return CORSPolicy{}, err
+	}
+	h := http.Header(header)
+	p := CORSPolicy{
+		AllowOrigins:  strings.Fields(h.Get("X-Container-Meta-Access-Control-Allow-Origin")),
+		ExposeHeaders: strings.Fields(h.Get("X-Container-Meta-Access-Control-Expose-Headers")),
+	}
+	if v := h.Get("X-Container-Meta-Access-Control-Max-Age"); v != "" {
+		if p.MaxAge, err = strconv.Atoi(v); err != nil {
+			return CORSPolicy{}, fmt.Errorf("Invalid X-Container-Meta-Access-Control-Max-Age: %q", v)
+		}
+	}
+	return p, nil
+}
+
+// SetContainerCORS validates p and replaces the CORS policy of the
+// container. Empty fields are removed.
+func (c *Client) SetContainerCORS(containerName string, p CORSPolicy) (http.Header, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	metadata := NewMetadata()
+	setOrDeleteMeta(&metadata, "X-Container-Meta-Access-Control-Allow-Origin", strings.Join(p.AllowOrigins, " "))
+	maxAge := ""
+	if p.MaxAge > 0 {
+		maxAge = strconv.Itoa(p.MaxAge)
+	}
+	setOrDeleteMeta(&metadata, "X-Container-Meta-Access-Control-Max-Age", maxAge)
+	setOrDeleteMeta(&metadata, "X-Container-Meta-Access-Control-Expose-Headers", strings.Join(p.ExposeHeaders, " "))
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+func (c *Client) ClearContainerCORS(containerName string) (http.Header, error) {
+	return c.SetContainerCORS(containerName, CORSPolicy{})
+}
+
+// PreflightResult is the outcome of a simulated CORS preflight.
+type PreflightResult struct {
+	// Allowed is true when the proxy accepted the preflight for the origin
+	// and listed the method in Access-Control-Allow-Methods.
+	Allowed       bool
+	StatusCode    int
+	AllowOrigin   string
+	AllowMethods  []string
+	AllowHeaders  []string
+	ExposeHeaders []string
+	MaxAge        int
+	Header        http.Header
+}
+
+// PreflightObject sends the OPTIONS request a browser at origin would send
+// before a cross-origin method request to the object, so a CORS policy can
+// be checked end to end. Like a browser, it sends no auth token.
+func (c *Client) PreflightObject(containerName string, objectName string, origin string, method string, headers []string) (*PreflightResult, error) {
+	c.setClient()
+	if err := c.setCredential(); err != nil {
+		return nil, err
+	}
+	urls := fmt.Sprintf("%s/%s/%s", strings.Trim(c.StorageUrl, "/"), containerName, objectName)
+	req, err := http.NewRequest("OPTIONS", urls, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if len(headers) != 0 {
+		req.Header.Set("Access-Control-Request-Headers", strings.Join(headers, ", "))
+	}
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+	result := &PreflightResult{
+		StatusCode:    res.StatusCode,
+		AllowOrigin:   res.Header.Get("Access-Control-Allow-Origin"),
+		AllowMethods:  splitHeaderList(res.Header.Get("Access-Control-Allow-Methods")),
+		AllowHeaders:  splitHeaderList(res.Header.Get("Access-Control-Allow-Headers")),
+		ExposeHeaders: splitHeaderList(res.Header.Get("Access-Control-Expose-Headers")),
+		Header:        res.Header,
+	}
+	result.MaxAge, _ = strconv.Atoi(res.Header.Get("Access-Control-Max-Age"))
+	result.Allowed = res.StatusCode >= 200 && res.StatusCode <= 299 &&
+		(result.AllowOrigin == "*" || result.AllowOrigin == origin) && containsFold(result.AllowMethods, method)
+	return result, nil
+}
+
+func containsFold(items []string, s string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitHeaderList(v string) []string {
+	var items []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			items = append(items, s)
+		}
+	}
+	return items
+}
@@ -0,0 +1,72 @@
+package goswift
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSPolicyValidate(t *testing.T) {
+	valid := CORSPolicy{AllowOrigins: []string{"https://example.com", "http://localhost:8080", "*"}, MaxAge: 600, ExposeHeaders: []string{"Etag", "X-Object-Meta-Author"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected error: %s", err)
+	}
+	for _, p := range []CORSPolicy{
+		{AllowOrigins: []string{"example.com"}},
+		{AllowOrigins: []string{"https://example.com/path"}},
+		{AllowOrigins: []string{"ftp://example.com"}},
+		{MaxAge: -1},
+		{ExposeHeaders: []string{"Bad Header"}},
+	} {
+		if err := p.Validate(); err == nil {
+			t.Errorf("Expected error: invalid policy was accepted: %+v", p)
+		}
+	}
+}
+
+func TestSplitHeaderList(t *testing.T) {
+	items := splitHeaderList("GET, HEAD,PUT , ")
+	if len(items) != 3 || items[2] != "PUT" {
+		t.Errorf("Expected error: unexpected items %v", items)
+	}
+}
+
+func TestPreflightObject(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "OPTIONS" || r.Header.Get("X-Auth-Token") != "" || r.URL.Path != "/v1/AUTH_test/c/o" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Origin") != "https://example.com" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", "https://example.com")
+		w.Header().Set("Access-Control-Allow-Methods", "HEAD, GET, PUT")
+		w.Header().Set("Access-Control-Max-Age", "600")
+	}))
+	defer srv.Close()
+	c := &Client{Token: "token", StorageUrl: srv.URL + "/v1/AUTH_test"}
+	for _, test := range []struct {
+		origin  string
+		method  string
+		allowed bool
+	}{
+		{"https://example.com", "GET", true},
+		{"https://example.com", "put", true},
+		{"https://example.com", "DELETE", false},
+		{"https://other.example.com", "GET", false},
+	} {
+		result, err := c.PreflightObject("c", "o", test.origin, test.method, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed != test.allowed {
+			t.Errorf("Expected error: %s from %s allowed is %v", test.method, test.origin, result.Allowed)
+		}
+	}
+	result, _ := c.PreflightObject("c", "o", "https://example.com", "GET", nil)
+	if result.MaxAge != 600 || len(result.AllowMethods) != 3 {
+		t.Errorf("Expected error: unexpected result %+v", result)
+	}
+}
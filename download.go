@@ -0,0 +1,172 @@
+package goswift
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DownloadOptions controls DownloadContainer.
+type DownloadOptions struct {
+	// Concurrency is the number of objects downloaded at once. Defaults to 8.
+	Concurrency int
+	// Prefix selects the objects to download and is removed from their
+	// local paths, so "photos/" downloads photos/a.jpg to dir/a.jpg.
+	Prefix string
+}
+
+// DownloadReport lists the objects DownloadContainer wrote and those whose
+// local copy was already up to date.
+type DownloadReport struct {
+	Downloaded []string
+	Unchanged  []string
+	Failed     []ObjectError
+}
+
+// DownloadContainer downloads the objects of containerName below
+// opts.Prefix to dir, creating directories from the "/" separators of their
+// names. Files whose MD5 matches the listing hash are not downloaded again.
+// Object content is streamed to a temporary file that replaces the local
+// file once its ETag checked out, and the file's mtime is restored from
+// X-Object-Meta-Mtime, or Last-Modified if the object has none.
+func (c *Client) DownloadContainer(containerName string, dir string, opts DownloadOptions) (*DownloadReport, error) {
+	if err := c.prepare(); err != nil {
+		return nil, err
+	}
+	objects, err := c.ListAllObjects(containerName, Params{Prefix: opts.Prefix})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	report := new(DownloadReport)
+	var mu sync.Mutex
+	parallel(opts.Concurrency, len(objects), func(i int) {
+		o := objects[i]
+		downloaded, err := c.downloadObject(containerName, o, dir, strings.TrimPrefix(o.Name, opts.Prefix))
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err != nil:
+			report.Failed = append(report.Failed, ObjectError{Name: o.Name, Err: err})
+		case downloaded:
+			report.Downloaded = append(report.Downloaded, o.Name)
+		default:
+			report.Unchanged = append(report.Unchanged, o.Name)
+		}
+	})
+	sort.Strings(report.Downloaded)
+	sort.Strings(report.Unchanged)
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].Name < report.Failed[j].Name })
+	if len(report.Failed) != 0 {
+		return report, fmt.Errorf("Could not download %d of %d objects.", len(report.Failed), len(objects))
+	}
+	return report, nil
+}
+
+func (c *Client) downloadObject(containerName string, o Object, dir string, rel string) (bool, error) {
+	localRel, err := localObjectPath(rel)
+	if strings.HasSuffix(o.Name, "/") || o.ContentType == "application/directory" {
+		if err != nil {
+			// the marker of the prefix itself
+			return false, nil
+		}
+		return false, os.MkdirAll(filepath.Join(dir, localRel), 0755)
+	}
+	if err != nil {
+		return false, err
+	}
+	path := filepath.Join(dir, localRel)
+	if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() && fi.Size() == int64(o.Bytes) {
+		if sum, err := fileMD5(path); err == nil && sum == o.Hash {
+			return false, nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+
+	body, header, err := c.GetObjectReader(containerName, o.Name)
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".goswift-")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	hash := md5.New()
+	_, err = io.Copy(tmp, io.TeeReader(body, hash))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return false, err
+	}
+	if !isManifest(header) {
+		sum := hex.EncodeToString(hash.Sum(nil))
+		if etag := strings.Trim(header.Get("Etag"), `"`); etag != "" && etag != sum {
+			return false, fmt.Errorf("%s was corrupted in transit: md5 %s, expected etag %s", o.Name, sum, etag)
+		}
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, err
+	}
+	if mtime, ok := downloadMtime(header); ok {
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func downloadMtime(h http.Header) (time.Time, bool) {
+	if t, ok := headerMtime(h); ok {
+		return t, true
+	}
+	t, err := http.ParseTime(h.Get("Last-Modified"))
+	return t, err == nil
+}
+
+// localObjectPath turns an object name into a relative local path. Empty,
+// "." and ".." segments are dropped or replaced so the path cannot escape the
+// download directory, and characters that are not valid in file names are
+// replaced with "_".
+func localObjectPath(name string) (string, error) {
+	var segments []string
+	for _, s := range strings.Split(name, "/") {
+		switch s {
+		case "", ".":
+			continue
+		case "..":
+			s = "__"
+		}
+		segments = append(segments, strings.Map(func(r rune) rune {
+			if r < 0x20 || r == 0x7f || (runtime.GOOS == "windows" && strings.ContainsRune(`<>:"\|?*`, r)) {
+				return '_'
+			}
+			return r
+		}, s))
+	}
+	if len(segments) == 0 {
+		return "", errors.New(fmt.Sprintf("Object name %q has no valid local path.", name))
+	}
+	return filepath.Join(segments...), nil
+}
@@ -0,0 +1,71 @@
+package goswift
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLocalObjectPath(t *testing.T) {
+	for name, expected := range map[string]string{
+		"a/b/c.txt":        filepath.Join("a", "b", "c.txt"),
+		"/a//b/./c.txt":    filepath.Join("a", "b", "c.txt"),
+		"../../etc/passwd": filepath.Join("__", "__", "etc", "passwd"),
+		"tab\there":        "tab_here",
+	} {
+		got, err := localObjectPath(name)
+		if err != nil || got != expected {
+			t.Errorf("Expected error: %q became %q, expected %q (%v)", name, got, expected, err)
+		}
+	}
+	for _, name := range []string{"", "/", "./."} {
+		if _, err := localObjectPath(name); err == nil {
+			t.Errorf("Expected error: %q was accepted", name)
+		}
+	}
+}
+
+func TestDownloadMtime(t *testing.T) {
+	h := http.Header{}
+	h.Set("Last-Modified", "Tue, 28 Oct 2014 16:05:42 GMT")
+	got, ok := downloadMtime(h)
+	if !ok || !got.Equal(time.Date(2014, 10, 28, 16, 5, 42, 0, time.UTC)) {
+		t.Errorf("Expected error: unexpected mtime %s", got)
+	}
+	h.Set("X-Object-Meta-Mtime", "1414512342.500000")
+	got, ok = downloadMtime(h)
+	if !ok || !got.Equal(time.Unix(1414512342, 500000000)) {
+		t.Errorf("Expected error: X-Object-Meta-Mtime was not preferred: %s", got)
+	}
+}
+
+func TestDownloadGzipObject(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("hello world"))
+	zw.Close()
+	s, c := newFakeSwift(t)
+	s.putObject("c", "logs/app.log.gz", buf.Bytes(), http.Header{"Content-Encoding": {"gzip"}})
+	dir, err := ioutil.TempDir("", "goswift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	report, err := c.DownloadContainer("c", dir, DownloadOptions{})
+	if err != nil {
+		t.Fatalf("Expected error: %s %+v", err, report)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "logs", "app.log.gz"))
+	if err != nil || !bytes.Equal(b, buf.Bytes()) {
+		t.Errorf("Expected error: gzip object was not downloaded as stored: %v", err)
+	}
+	b, err = c.FS("c").ReadFile("logs/app.log.gz")
+	if err != nil || !bytes.Equal(b, buf.Bytes()) {
+		t.Errorf("Expected error: gzip object was not read as stored: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package goswift
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SetDeleteAt schedules the object for deletion at t.
+func (m *Metadata) SetDeleteAt(t time.Time) {
+	m.SetMeta("X-Delete-At", fmt.Sprintf("%d", t.Unix()))
+}
+
+// SetDeleteAfter schedules the object for deletion d from now, rounded up to
+// whole seconds.
+func (m *Metadata) SetDeleteAfter(d time.Duration) {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	m.SetMeta("X-Delete-After", fmt.Sprintf("%d", seconds))
+}
+
+// SetObjectExpiry schedules an existing object for deletion at t. A POST
+// replaces all object metadata, so the current metadata is sent along.
+func (c *Client) SetObjectExpiry(containerName string, objectName string, t time.Time) (http.Header, error) {
+	return c.updateObjectExpiry(containerName, objectName, func(m *Metadata) { m.SetDeleteAt(t) })
+}
+
+// SetObjectExpiryAfter schedules an existing object for deletion d from now.
+func (c *Client) SetObjectExpiryAfter(containerName string, objectName string, d time.Duration) (http.Header, error) {
+	return c.updateObjectExpiry(containerName, objectName, func(m *Metadata) { m.SetDeleteAfter(d) })
+}
+
+// ClearObjectExpiry cancels the scheduled deletion of an object.
+func (c *Client) ClearObjectExpiry(containerName string, objectName string) (http.Header, error) {
+	return c.updateObjectExpiry(containerName, objectName, func(m *Metadata) {
+		http.Header(*m).Del("X-Delete-At")
+	})
+}
+
+func (c *Client) updateObjectExpiry(containerName string, objectName string, update func(*Metadata)) (http.Header, error) {
+	header, err := c.ShowObjectMeta(containerName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	metadata := preservedMetadata(header)
+	update(&metadata)
+	return c.CreateObjectMeta(containerName, objectName, metadata)
+}
+
+// ObjectExpiry returns when the object is scheduled for deletion, or the
+// zero time if it is not.
+func (c *Client) ObjectExpiry(containerName string, objectName string) (time.Time, error) {
+	info, err := c.StatObject(containerName, objectName)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.DeleteAt, nil
+}
+
+// ExpiringObject is an object scheduled for deletion.
+type ExpiringObject struct {
+	Object
+	DeleteAt time.Time
+}
+
+// ListExpiringObjects returns the objects of the listing selected by p that
+// are scheduled for deletion before t, soonest first. Listings do not carry
+// X-Delete-At, so every object is checked with a HEAD.
+func (c *Client) ListExpiringObjects(containerName string, before time.Time, p Params) ([]ExpiringObject, error) {
+	if err := c.prepare(); err != nil {
+		return nil, err
+	}
+	objects, err := c.ListAllObjects(containerName, p)
+	if err != nil {
+		return nil, err
+	}
+	var mu sync.Mutex
+	var expiring []ExpiringObject
+	var firstErr error
+	parallel(defaultConcurrency, len(objects), func(i int) {
+		deleteAt, err := c.ObjectExpiry(containerName, objects[i].Name)
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err != nil && !isNotFound(err):
+			if firstErr == nil {
+				firstErr = err
+			}
+		case err == nil && !deleteAt.IsZero() && deleteAt.Before(before):
+			expiring = append(expiring, ExpiringObject{Object: objects[i], DeleteAt: deleteAt})
+		}
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	sort.Slice(expiring, func(i, j int) bool { return expiring[i].DeleteAt.Before(expiring[j].DeleteAt) })
+	return expiring, nil
+}
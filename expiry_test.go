@@ -0,0 +1,65 @@
+package goswift
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSetDeleteAtAndAfter(t *testing.T) {
+	metadata := NewMetadata()
+	metadata.SetDeleteAt(time.Unix(1500000000, 999))
+	metadata.SetDeleteAfter(1500 * time.Millisecond)
+	h := http.Header(metadata)
+	if h.Get("X-Delete-At") != "1500000000" {
+		t.Errorf("Expected error: unexpected X-Delete-At %s", h.Get("X-Delete-At"))
+	}
+	if h.Get("X-Delete-After") != "2" {
+		t.Errorf("Expected error: unexpected X-Delete-After %s", h.Get("X-Delete-After"))
+	}
+	metadata.SetDeleteAfter(0)
+	if h.Get("X-Delete-After") != "1" {
+		t.Errorf("Expected error: unexpected X-Delete-After %s", h.Get("X-Delete-After"))
+	}
+}
+
+func TestObjectExpiryKeepsManifest(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "seg/1", []byte("hello "), nil)
+	s.putObject("c", "seg/2", []byte("world"), nil)
+	s.putObject("c", "big", nil, http.Header{
+		"Content-Type":       {"text/plain"},
+		"X-Object-Manifest":  {"c/seg/"},
+		"X-Object-Meta-Mood": {"happy"},
+		"Cache-Control":      {"max-age=60"},
+		"Content-Language":   {"en"},
+		"Expires":            {"Wed, 21 Oct 2026 07:28:00 GMT"},
+		"X-Robots-Tag":       {"noindex"},
+	})
+	check := func(deleteAt string) {
+		o := s.object("c", "big")
+		for k, v := range map[string]string{"X-Object-Manifest": "c/seg/", "X-Object-Meta-Mood": "happy", "Cache-Control": "max-age=60",
+			"Content-Language": "en", "Expires": "Wed, 21 Oct 2026 07:28:00 GMT", "X-Robots-Tag": "noindex", "Content-Type": "text/plain", "X-Delete-At": deleteAt} {
+			if o.header.Get(k) != v {
+				t.Errorf("Expected error: %s is %q after the POST", k, o.header.Get(k))
+			}
+		}
+	}
+	if _, err := c.SetObjectExpiry("c", "big", time.Unix(1900000000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	check("1900000000")
+	if _, err := c.ClearObjectExpiry("c", "big"); err != nil {
+		t.Fatal(err)
+	}
+	check("")
+	body, _, err := c.GetObjectReader("c", "big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if b, _ := ioutil.ReadAll(body); string(b) != "hello world" {
+		t.Errorf("Expected error: unexpected content %q", b)
+	}
+}
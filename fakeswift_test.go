@@ -0,0 +1,565 @@
+package goswift
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSwift is an in-memory Swift cluster with a single account, enough to
+// exercise the client against real HTTP round trips.
+type fakeSwift struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu   sync.Mutex
+	info string
+	// ignoreRange serves whole objects to ranged GETs, as some proxies do.
+	ignoreRange bool
+	// listingLimit is the page size of container listings, 10000 if unset.
+	listingLimit int
+	account      http.Header
+	containers   map[string]*fakeContainer
+	requests     []fakeRequest
+	// fail returns a status code to answer a request with instead of
+	// handling it, or 0.
+	fail func(r *http.Request) int
+}
+
+type fakeContainer struct {
+	header  http.Header
+	objects map[string]*fakeStored
+}
+
+type fakeStored struct {
+	data     []byte
+	header   http.Header
+	modified time.Time
+	// manifest is set for static large objects.
+	manifest []SLOSegment
+}
+
+type fakeRequest struct {
+	Method           string
+	Path             string
+	Query            url.Values
+	Header           http.Header
+	ContentLength    int64
+	TransferEncoding []string
+	Body             []byte
+}
+
+const fakeAccount = "AUTH_test"
+
+// newFakeSwift starts a cluster and returns a client for its account. The
+// cluster does not publish /info unless info is set.
+func newFakeSwift(t *testing.T) (*fakeSwift, *Client) {
+	s := &fakeSwift{t: t, account: make(http.Header), containers: make(map[string]*fakeContainer)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.server.Close)
+	c := &Client{Token: "token", StorageUrl: s.server.URL + "/v1/" + fakeAccount}
+	return s, c
+}
+
+func (s *fakeSwift) putObject(container string, name string, data []byte, header http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ct := s.containers[container]
+	if ct == nil {
+		ct = &fakeContainer{header: make(http.Header), objects: make(map[string]*fakeStored)}
+		s.containers[container] = ct
+	}
+	h := make(http.Header)
+	for k, v := range header {
+		h[k] = v
+	}
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", "application/octet-stream")
+	}
+	ct.objects[name] = &fakeStored{data: data, header: h, modified: time.Now()}
+}
+
+func (s *fakeSwift) object(container string, name string) *fakeStored {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ct := s.containers[container]; ct != nil {
+		return ct.objects[name]
+	}
+	return nil
+}
+
+func (s *fakeSwift) objectNames(container string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	if ct := s.containers[container]; ct != nil {
+		for name := range ct.objects {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// recorded returns the requests made with method.
+func (s *fakeSwift) recorded(method string) []fakeRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var reqs []fakeRequest
+	for _, r := range s.requests {
+		if r.Method == method {
+			reqs = append(reqs, r)
+		}
+	}
+	return reqs
+}
+
+func md5Hex(b []byte) string {
+	sum := md5.Sum(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func (o *fakeStored) etag() string {
+	if o.manifest != nil {
+		var etags string
+		for _, s := range o.manifest {
+			etags += s.Etag
+		}
+		return md5Hex([]byte(etags))
+	}
+	return md5Hex(o.data)
+}
+
+func (s *fakeSwift) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, fakeRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(),
+		Header: r.Header.Clone(), ContentLength: r.ContentLength, TransferEncoding: r.TransferEncoding, Body: body})
+	if s.fail != nil {
+		if code := s.fail(r); code != 0 {
+			w.WriteHeader(code)
+			return
+		}
+	}
+	if r.URL.Path == "/info" {
+		if s.info == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(s.info))
+		return
+	}
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v1/"), "/", 3)
+	if parts[0] != fakeAccount || r.Header.Get("X-Auth-Token") != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case len(parts) == 1, len(parts) == 2 && parts[1] == "":
+		s.serveAccount(w, r, body)
+	case len(parts) == 2:
+		s.serveContainer(w, r, parts[1])
+	default:
+		s.serveObject(w, r, parts[1], parts[2], body)
+	}
+}
+
+// updateMeta applies the metadata headers of a request with the prefix,
+// including X-Remove- headers.
+func updateMeta(h http.Header, r *http.Request, prefix string, extra ...string) {
+	for k, v := range r.Header {
+		k = http.CanonicalHeaderKey(k)
+		switch {
+		case strings.HasPrefix(k, "X-Remove-"):
+			h.Del("X-" + strings.TrimPrefix(k, "X-Remove-"))
+		case strings.HasPrefix(k, prefix):
+			if v[0] == "" {
+				h.Del(k)
+			} else {
+				h.Set(k, v[0])
+			}
+		default:
+			for _, e := range extra {
+				if k == e {
+					h.Set(k, v[0])
+				}
+			}
+		}
+	}
+}
+
+func (s *fakeSwift) serveAccount(w http.ResponseWriter, r *http.Request, body []byte) {
+	if _, ok := r.URL.Query()["bulk-delete"]; ok && r.Method == "POST" {
+		s.bulkDelete(w, body)
+		return
+	}
+	switch r.Method {
+	case "HEAD":
+		copyHeader(w.Header(), s.account)
+		w.WriteHeader(http.StatusNoContent)
+	case "POST":
+		updateMeta(s.account, r, "X-Account-Meta-", "X-Account-Access-Control")
+		w.WriteHeader(http.StatusNoContent)
+	case "GET":
+		var names []string
+		for name := range s.containers {
+			if name > r.URL.Query().Get("marker") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		list := make([]Container, 0, len(names))
+		for _, name := range names {
+			list = append(list, Container{Name: name, Count: uint(len(s.containers[name].objects))})
+		}
+		json.NewEncoder(w).Encode(list)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// bulkDelete deletes the newline separated, escaped paths of body like the
+// bulk middleware.
+func (s *fakeSwift) bulkDelete(w http.ResponseWriter, body []byte) {
+	result := map[string]interface{}{"Response Status": "200 OK", "Response Body": ""}
+	deleted, notFound := 0, 0
+	errs := make([][]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(string(body)), "\n") {
+		p, err := url.PathUnescape(line)
+		if err != nil {
+			errs = append(errs, []string{line, "400 Bad Request"})
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(p, "/"), "/", 2)
+		ct := s.containers[parts[0]]
+		switch {
+		case ct == nil:
+			notFound++
+		case len(parts) == 1:
+			if len(ct.objects) != 0 {
+				errs = append(errs, []string{p, "409 Conflict"})
+				continue
+			}
+			delete(s.containers, parts[0])
+			deleted++
+		case ct.objects[parts[1]] == nil:
+			notFound++
+		default:
+			delete(ct.objects, parts[1])
+			deleted++
+		}
+	}
+	if len(errs) != 0 {
+		result["Response Status"] = "400 Bad Request"
+	}
+	result["Number Deleted"], result["Number Not Found"], result["Errors"] = deleted, notFound, errs
+	json.NewEncoder(w).Encode(result)
+}
+
+func copyHeader(dst http.Header, src http.Header) {
+	for k, v := range src {
+		dst[k] = v
+	}
+}
+
+func (s *fakeSwift) serveContainer(w http.ResponseWriter, r *http.Request, name string) {
+	ct := s.containers[name]
+	if ct == nil && r.Method != "PUT" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case "PUT":
+		status := http.StatusAccepted
+		if ct == nil {
+			ct = &fakeContainer{header: make(http.Header), objects: make(map[string]*fakeStored)}
+			s.containers[name] = ct
+			status = http.StatusCreated
+		}
+		updateMeta(ct.header, r, "X-Container-Meta-", "X-Container-Read", "X-Container-Write", "X-Container-Sync-To", "X-Container-Sync-Key", "X-Storage-Policy", "X-Versions-Location")
+		w.WriteHeader(status)
+	case "POST":
+		updateMeta(ct.header, r, "X-Container-Meta-", "X-Container-Read", "X-Container-Write", "X-Container-Sync-To", "X-Container-Sync-Key", "X-Versions-Location")
+		w.WriteHeader(http.StatusNoContent)
+	case "HEAD":
+		copyHeader(w.Header(), ct.header)
+		w.Header().Set("X-Container-Object-Count", strconv.Itoa(len(ct.objects)))
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE":
+		if len(ct.objects) != 0 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		delete(s.containers, name)
+		w.WriteHeader(http.StatusNoContent)
+	case "GET":
+		s.list(w, r, ct)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeSwift) list(w http.ResponseWriter, r *http.Request, ct *fakeContainer) {
+	q := r.URL.Query()
+	prefix, delimiter, marker := q.Get("prefix"), q.Get("delimiter"), q.Get("marker")
+	limit := 10000
+	if s.listingLimit != 0 {
+		limit = s.listingLimit
+	}
+	if v := q.Get("limit"); v != "" {
+		if n, _ := strconv.Atoi(v); n < limit {
+			limit = n
+		}
+	}
+	var names []string
+	for name := range ct.objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]map[string]interface{}, 0)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if len(list) >= limit {
+			break
+		}
+		if !strings.HasPrefix(name, prefix) || name <= marker {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				subdir := name[:len(prefix)+i+len(delimiter)]
+				if !seen[subdir] && subdir > marker {
+					seen[subdir] = true
+					list = append(list, map[string]interface{}{"subdir": subdir})
+				}
+				continue
+			}
+		}
+		o := ct.objects[name]
+		list = append(list, map[string]interface{}{
+			"name": name, "hash": o.etag(), "bytes": len(s.content(o)), "content_type": o.header.Get("Content-Type"),
+			"last_modified": o.modified.UTC().Format("2006-01-02T15:04:05.000000"),
+		})
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+func (s *fakeSwift) serveObject(w http.ResponseWriter, r *http.Request, container string, name string, body []byte) {
+	ct := s.containers[container]
+	if ct == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	o := ct.objects[name]
+	q := r.URL.Query()
+	switch r.Method {
+	case "PUT":
+		s.put(w, r, ct, name, body)
+	case "COPY":
+		if o == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		destination, _ := url.PathUnescape(r.Header.Get("Destination"))
+		dest := strings.SplitN(strings.TrimPrefix(destination, "/"), "/", 2)
+		dct := s.containers[dest[0]]
+		if dct == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		cp := &fakeStored{header: make(http.Header), modified: time.Now()}
+		if q.Get("multipart-manifest") == "get" {
+			cp.data, cp.manifest = o.data, o.manifest
+			copyHeader(cp.header, o.header)
+		} else {
+			cp.data = s.content(o)
+			for k, v := range o.header {
+				if k != "X-Object-Manifest" && k != "X-Static-Large-Object" {
+					cp.header[k] = v
+				}
+			}
+		}
+		updateMeta(cp.header, r, "X-Object-Meta-", "Content-Type")
+		dct.objects[dest[1]] = cp
+		w.WriteHeader(http.StatusCreated)
+	case "POST":
+		if o == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// A POST replaces all metadata that is not system metadata.
+		h := make(http.Header)
+		h.Set("Content-Type", o.header.Get("Content-Type"))
+		if o.manifest != nil {
+			h.Set("X-Static-Large-Object", "True")
+		}
+		for k, v := range r.Header {
+			k = http.CanonicalHeaderKey(k)
+			if fakeObjectHeader(k) {
+				h.Set(k, v[0])
+			}
+		}
+		o.header = h
+		w.WriteHeader(http.StatusAccepted)
+	case "DELETE":
+		if o == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(ct.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	case "HEAD", "GET":
+		if o == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		etag := o.etag()
+		copyHeader(w.Header(), o.header)
+		if o.manifest != nil {
+			w.Header().Set("Etag", `"`+etag+`"`)
+		} else {
+			w.Header().Set("Etag", etag)
+		}
+		// Swift rounds Last-Modified up to the second.
+		w.Header().Set("Last-Modified", o.modified.UTC().Add(time.Second-1).Truncate(time.Second).Format(http.TimeFormat))
+		if m := r.Header.Get("If-Match"); m != "" && strings.Trim(m, `"`) != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data := o.data
+		if o.manifest != nil && q.Get("multipart-manifest") == "get" {
+			entries := make([]SLOManifestEntry, len(o.manifest))
+			for i, seg := range o.manifest {
+				entries[i] = SLOManifestEntry{Name: seg.Path, Hash: seg.Etag, Bytes: seg.SizeBytes}
+			}
+			data, _ = json.Marshal(entries)
+		} else {
+			data = s.content(o)
+		}
+		status := http.StatusOK
+		if rng := r.Header.Get("Range"); rng != "" && !s.ignoreRange && q.Get("multipart-manifest") != "get" {
+			var start, end int
+			if n, _ := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end); n != 2 || end >= len(data) {
+				end = len(data) - 1
+			}
+			data = data[start : end+1]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == "GET" {
+			w.Write(data)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// fakeObjectHeader reports whether a request header is stored with an
+// object.
+func fakeObjectHeader(k string) bool {
+	switch k {
+	case "Content-Type", "Content-Encoding", "Content-Disposition", "Content-Language", "Cache-Control",
+		"Expires", "X-Robots-Tag", "X-Delete-At", "X-Object-Manifest":
+		return true
+	}
+	return strings.HasPrefix(k, "X-Object-Meta-")
+}
+
+func (s *fakeSwift) put(w http.ResponseWriter, r *http.Request, ct *fakeContainer, name string, body []byte) {
+	o := &fakeStored{data: body, header: make(http.Header), modified: time.Now()}
+	for k, v := range r.Header {
+		k = http.CanonicalHeaderKey(k)
+		if fakeObjectHeader(k) {
+			o.header.Set(k, v[0])
+		}
+	}
+	if o.header.Get("Content-Type") == "" {
+		o.header.Set("Content-Type", "application/octet-stream")
+	}
+	if src, _ := url.PathUnescape(r.Header.Get("X-Copy-From")); src != "" {
+		parts := strings.SplitN(strings.TrimPrefix(src, "/"), "/", 2)
+		sct := s.containers[parts[0]]
+		if sct == nil || sct.objects[parts[1]] == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		o.data = s.content(sct.objects[parts[1]])
+	}
+	if r.URL.Query().Get("multipart-manifest") == "put" {
+		var segments []SLOSegment
+		if err := json.Unmarshal(body, &segments); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for i, seg := range segments {
+			parts := strings.SplitN(strings.TrimPrefix(seg.Path, "/"), "/", 2)
+			sct := s.containers[parts[0]]
+			if sct == nil || sct.objects[parts[1]] == nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			so := sct.objects[parts[1]]
+			if seg.Etag != "" && seg.Etag != so.etag() {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			segments[i].Etag = so.etag()
+			segments[i].SizeBytes = int64(len(s.content(so)))
+		}
+		o.data, o.manifest = nil, segments
+		o.header.Set("X-Static-Large-Object", "True")
+	} else if etag := r.Header.Get("Etag"); etag != "" && strings.Trim(etag, `"`) != md5Hex(o.data) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+	ct.objects[name] = o
+	w.Header().Set("Etag", o.etag())
+	w.WriteHeader(http.StatusCreated)
+}
+
+// content returns what a GET of o returns. s.mu must be held.
+func (s *fakeSwift) content(o *fakeStored) []byte {
+	if o.manifest != nil {
+		var data []byte
+		for _, seg := range o.manifest {
+			parts := strings.SplitN(strings.TrimPrefix(seg.Path, "/"), "/", 2)
+			if sct := s.containers[parts[0]]; sct != nil && sct.objects[parts[1]] != nil {
+				data = append(data, s.content(sct.objects[parts[1]])...)
+			}
+		}
+		return data
+	}
+	if manifest := o.header.Get("X-Object-Manifest"); manifest != "" {
+		parts := strings.SplitN(manifest, "/", 2)
+		sct := s.containers[parts[0]]
+		if sct == nil {
+			return nil
+		}
+		var names []string
+		for name := range sct.objects {
+			if strings.HasPrefix(name, parts[1]) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		var data []byte
+		for _, name := range names {
+			data = append(data, sct.objects[name].data...)
+		}
+		return data
+	}
+	return o.data
+}
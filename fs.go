@@ -0,0 +1,342 @@
+package goswift
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DirectoryType is the content type of directory marker objects.
+const DirectoryType = "application/directory"
+
+// FS is a read only fs.FS over the objects of a container. Object names are
+// slash separated paths, and both the prefixes they share and directory
+// marker objects are directories.
+type FS struct {
+	client    *Client
+	container string
+}
+
+// FS returns a file system over the objects of containerName.
+func (c *Client) FS(containerName string) *FS {
+	return &FS{client: c, container: containerName}
+}
+
+var (
+	_ fs.FS         = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.ReadFileFS = (*FS)(nil)
+)
+
+func (f *FS) Open(name string) (fs.File, error) {
+	info, err := f.stat("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return &fsDir{fs: f, name: name, info: info}, nil
+	}
+	return &fsFile{fs: f, name: name, info: info}, nil
+}
+
+// Stat returns the FileInfo of an object from a HEAD, or of a directory.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	return f.stat("stat", name)
+}
+
+func (f *FS) stat(op string, name string) (*fsFileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return &fsFileInfo{name: ".", dir: true}, nil
+	}
+	header, err := f.client.ShowObjectMeta(f.container, name)
+	if err == nil {
+		info, err := ParseObjectInfo(header)
+		if err != nil {
+			return nil, &fs.PathError{Op: op, Path: name, Err: err}
+		}
+		return &fsFileInfo{name: path.Base(name), size: info.ContentLength, modTime: info.LastModified,
+			dir: info.ContentType == DirectoryType, sys: info}, nil
+	}
+	if !isNotFound(err) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fsError(err)}
+	}
+	objects, _, err := f.client.ListObjectsWithParams(f.container, Params{Prefix: name + "/", Limit: 1})
+	if err != nil {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fsError(err)}
+	}
+	if len(objects) == 0 {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return &fsFileInfo{name: path.Base(name), dir: true}, nil
+}
+
+// ReadDir lists a directory with a delimiter listing, so it does not HEAD
+// the entries.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	prefix := ""
+	if name != "." {
+		prefix = name + "/"
+	}
+	objects, err := f.client.ListAllObjects(f.container, Params{Prefix: prefix, Delimiter: "/"})
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fsError(err)}
+	}
+	entries := make(map[string]fs.DirEntry)
+	for _, o := range objects {
+		var info *fsFileInfo
+		switch {
+		case o.Subdir != "":
+			info = &fsFileInfo{name: strings.TrimSuffix(strings.TrimPrefix(o.Subdir, prefix), "/"), dir: true}
+		case o.Name == prefix:
+			// the marker of the directory itself
+			continue
+		default:
+			o := o
+			info = &fsFileInfo{name: strings.TrimPrefix(o.Name, prefix), size: int64(o.Bytes),
+				modTime: parseListingTime(o.LastModified), dir: o.ContentType == DirectoryType, sys: &o}
+		}
+		if info.name == "" || strings.Contains(info.name, "/") {
+			// not a valid fs path, e.g. "a//b"
+			continue
+		}
+		if e, ok := entries[info.name]; ok && e.IsDir() {
+			continue
+		}
+		entries[info.name] = fs.FileInfoToDirEntry(info)
+	}
+	if len(entries) == 0 && name != "." {
+		info, err := f.stat("readdir", name)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+		}
+	}
+	list := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list, nil
+}
+
+// ReadFile reads an object with a single GET.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	body, header, err := f.client.GetObjectReader(f.container, name)
+	if err != nil {
+		if isNotFound(err) {
+			if info, serr := f.stat("readfile", name); serr == nil && info.IsDir() {
+				return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+			}
+		}
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fsError(err)}
+	}
+	defer body.Close()
+	if header.Get("Content-Type") == DirectoryType {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errors.New("is a directory")}
+	}
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
+	}
+	return b, nil
+}
+
+// fsError maps Swift errors to their fs equivalents.
+func fsError(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		switch e.Code {
+		case http.StatusNotFound:
+			return fs.ErrNotExist
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fs.ErrPermission
+		}
+	}
+	return err
+}
+
+// parseListingTime parses the last_modified of a container listing. Swift
+// rounds the Last-Modified of a HEAD up to the second, so the same is done
+// here for ReadDir and Stat to agree.
+func parseListingTime(v string) time.Time {
+	t, err := time.Parse("2006-01-02T15:04:05.999999", v)
+	if err != nil {
+		return time.Time{}
+	}
+	if r := t.Truncate(time.Second); !r.Equal(t) {
+		return r.Add(time.Second)
+	}
+	return t
+}
+
+type fsFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+	sys     interface{}
+}
+
+func (i *fsFileInfo) Name() string       { return i.name }
+func (i *fsFileInfo) Size() int64        { return i.size }
+func (i *fsFileInfo) ModTime() time.Time { return i.modTime }
+func (i *fsFileInfo) IsDir() bool        { return i.dir }
+func (i *fsFileInfo) Sys() interface{}   { return i.sys }
+
+func (i *fsFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+// fsFile reads an object. The content is fetched on the first Read and
+// again with a Range header after a Seek.
+type fsFile struct {
+	fs     *FS
+	name   string
+	info   *fsFileInfo
+	body   io.ReadCloser
+	offset int64
+	closed bool
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *fsFile) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.offset >= f.info.size {
+		return 0, io.EOF
+	}
+	if f.body == nil {
+		body, err := f.fs.client.getObjectRange(f.fs.container, f.name, f.offset, -1, "")
+		if err != nil {
+			return 0, &fs.PathError{Op: "read", Path: f.name, Err: fsError(err)}
+		}
+		f.body = body
+	}
+	n, err := f.body.Read(p)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *fsFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.size
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if offset != f.offset && f.body != nil {
+		f.body.Close()
+		f.body = nil
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *fsFile) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	if f.body != nil {
+		return f.body.Close()
+	}
+	return nil
+}
+
+type fsDir struct {
+	fs      *FS
+	name    string
+	info    *fsFileInfo
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *fsDir) Close() error { return nil }
+
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fs.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// getObjectRange returns length bytes of an object starting at offset, or
+// the rest of it if length is negative. If etag is set, the GET fails with
+// 412 unless the object still has that ETag.
+func (c *Client) getObjectRange(containerName string, objectName string, offset int64, length int64, etag string) (io.ReadCloser, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	header := make(http.Header)
+	if etag != "" {
+		header.Set("If-Match", etag)
+	}
+	if length < 0 {
+		header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	} else {
+		header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	}
+	res, err := c.storageRequest("GET", objectPath, nil, 0, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusOK && offset > 0 {
+		// the Range header was ignored
+		if _, err := io.CopyN(ioutil.Discard, res.Body, offset); err != nil {
+			res.Body.Close()
+			return nil, err
+		}
+	}
+	return res.Body, nil
+}
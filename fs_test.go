@@ -0,0 +1,110 @@
+package goswift
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestParseListingTime(t *testing.T) {
+	got := parseListingTime("2014-10-28T16:05:42.123450")
+	if !got.Equal(time.Date(2014, 10, 28, 16, 5, 43, 0, time.UTC)) {
+		t.Errorf("Expected error: unexpected time %s", got)
+	}
+	got = parseListingTime("2014-10-28T16:05:42.000000")
+	if !got.Equal(time.Date(2014, 10, 28, 16, 5, 42, 0, time.UTC)) {
+		t.Errorf("Expected error: unexpected time %s", got)
+	}
+	if !parseListingTime("yesterday").IsZero() {
+		t.Errorf("Expected error: invalid time was parsed")
+	}
+}
+
+func TestFSError(t *testing.T) {
+	if !errors.Is(fsError(&Error{Code: http.StatusNotFound}), fs.ErrNotExist) {
+		t.Errorf("Expected error: 404 is not fs.ErrNotExist")
+	}
+	if !errors.Is(fsError(&Error{Code: http.StatusForbidden}), fs.ErrPermission) {
+		t.Errorf("Expected error: 403 is not fs.ErrPermission")
+	}
+	if !errors.Is(fsError(&QuotaExceededError{Err: &Error{Code: http.StatusNotFound}}), fs.ErrNotExist) {
+		t.Errorf("Expected error: wrapped 404 is not fs.ErrNotExist")
+	}
+	if _, ok := fsError(&Error{Code: http.StatusInternalServerError}).(*Error); !ok {
+		t.Errorf("Expected error: 500 was not passed through")
+	}
+}
+
+func TestFSInvalidPath(t *testing.T) {
+	fsys := new(Client).FS("test")
+	for _, name := range []string{"/a", "a/", "a/../b", ""} {
+		if _, err := fsys.Open(name); !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Expected error: %q was not rejected: %v", name, err)
+		}
+	}
+	info, err := fsys.Stat(".")
+	if err != nil || !info.IsDir() || info.Mode() != fs.ModeDir|0555 {
+		t.Errorf("Expected error: root is not a directory: %v", err)
+	}
+}
+
+func TestFSDirReadDir(t *testing.T) {
+	d := &fsDir{name: ".", read: true}
+	for _, name := range []string{"a", "b", "c"} {
+		d.entries = append(d.entries, fs.FileInfoToDirEntry(&fsFileInfo{name: name}))
+	}
+	entries, err := d.ReadDir(2)
+	if err != nil || len(entries) != 2 || entries[1].Name() != "b" {
+		t.Errorf("Expected error: unexpected entries %v, %v", entries, err)
+	}
+	entries, err = d.ReadDir(2)
+	if err != nil || len(entries) != 1 || entries[0].Name() != "c" {
+		t.Errorf("Expected error: unexpected entries %v, %v", entries, err)
+	}
+	if _, err = d.ReadDir(2); err != io.EOF {
+		t.Errorf("Expected error: expected io.EOF, got %v", err)
+	}
+}
+
+func TestFSFileSeek(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "docs/hello.txt", []byte("hello world"), nil)
+	for _, ignoreRange := range []bool{false, true} {
+		s.mu.Lock()
+		s.ignoreRange = ignoreRange
+		s.mu.Unlock()
+		f, err := c.FS("c").Open("docs/hello.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.(io.Seeker).Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(f)
+		f.Close()
+		if err != nil || string(b) != "world" {
+			t.Errorf("Expected error: read %q after seeking with ignoreRange %v: %v", b, ignoreRange, err)
+		}
+	}
+}
+
+func TestFSFake(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "a", nil, http.Header{"Content-Type": {DirectoryType}})
+	s.putObject("c", "a/b.txt", []byte("hello"), nil)
+	s.putObject("c", "top.txt", []byte("top"), nil)
+	if err := fstest.TestFS(c.FS("c"), "a/b.txt", "top.txt"); err != nil {
+		t.Errorf("Expected error: %s", err)
+	}
+	if _, err := c.FS("c").ReadDir("top.txt"); err == nil {
+		t.Errorf("Expected error: %s", "A file was read as a directory.")
+	}
+	if _, err := c.FS("c").ReadDir("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected error: missing directory was read: %v", err)
+	}
+}
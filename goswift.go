@@ -2,6 +2,7 @@ package goswift
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
@@ -100,6 +101,9 @@ type Client struct {
 	RegionName  string
 	SkipSecure  bool
 	ChunkSize   uint
+	// Capabilities caches the cluster's /info. It is fetched on first use
+	// when nil.
+	Capabilities *Capabilities
 }
 
 func (c *Client) SWAuthV1() error {
@@ -206,9 +210,33 @@ func (c *Client) setCredential() (err error) {
 }
 
 func (c *Client) request(method string, path string, body io.Reader, contentLength int64, header http.Header, params url.Values) ([]byte, map[string][]string, error) {
+	return c.requestContext(context.Background(), method, path, body, contentLength, header, params)
+}
+
+// requestContext is like request but aborts when ctx is done.
+func (c *Client) requestContext(ctx context.Context, method string, path string, body io.Reader, contentLength int64, header http.Header, params url.Values) ([]byte, map[string][]string, error) {
+	res, err := c.storageRequestContext(ctx, method, path, body, contentLength, header, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	resbody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resbody, res.Header, err
+}
+
+// storageRequest is like request but leaves reading and closing the
+// response body to the caller.
+func (c *Client) storageRequest(method string, path string, body io.Reader, contentLength int64, header http.Header, params url.Values) (*http.Response, error) {
+	return c.storageRequestContext(context.Background(), method, path, body, contentLength, header, params)
+}
+
+func (c *Client) storageRequestContext(ctx context.Context, method string, path string, body io.Reader, contentLength int64, header http.Header, params url.Values) (*http.Response, error) {
 	c.setClient()
 	if err := c.setCredential(); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	urls := fmt.Sprintf("%s/%s", strings.Trim(c.StorageUrl, "/"), path)
 	if params == nil {
@@ -216,22 +244,30 @@ func (c *Client) request(method string, path string, body io.Reader, contentLeng
 	}
 	params.Set("format", "json")
 	urls += "?" + params.Encode()
-	req, _ := http.NewRequest(method, urls, body)
+	return c.doContext(ctx, method, urls, body, contentLength, header)
+}
+
+func (c *Client) do(method string, urls string, body io.Reader, contentLength int64, header http.Header) (*http.Response, error) {
+	return c.doContext(context.Background(), method, urls, body, contentLength, header)
+}
+
+func (c *Client) doContext(ctx context.Context, method string, urls string, body io.Reader, contentLength int64, header http.Header) (*http.Response, error) {
+	c.setClient()
+	req, err := http.NewRequestWithContext(ctx, method, urls, body)
+	if err != nil {
+		return nil, err
+	}
 	req = c.setHeaders(req, header)
 	req.ContentLength = contentLength
 	res, err := c.Client.Do(req)
-	defer res.Body.Close()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if err := CheckResponse(res); err != nil {
-		return nil, nil, err
+		res.Body.Close()
+		return nil, err
 	}
-	resbody, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, nil, err
-	}
-	return resbody, res.Header, err
+	return res, nil
 }
 
 type Params struct {
@@ -241,6 +277,8 @@ type Params struct {
 	Prefix    string
 	Delimiter string
 	Path      string
+	// VersionMarker is only used when listing object versions.
+	VersionMarker string
 }
 
 func (p *Params) setQueryParams(params url.Values) {
@@ -259,9 +297,12 @@ func (p *Params) setQueryParams(params url.Values) {
 	if p.Delimiter != "" {
 		params.Set("delimiter", fmt.Sprintf("%v", p.Delimiter))
 	}
-	if p.Endmarker != "" {
+	if p.Path != "" {
 		params.Set("path", fmt.Sprintf("%v", p.Path))
 	}
+	if p.VersionMarker != "" {
+		params.Set("version_marker", fmt.Sprintf("%v", p.VersionMarker))
+	}
 }
 
 type Metadata http.Header
@@ -331,8 +372,7 @@ func (c *Client) ListContainersWithParams(p Params) ([]Container, http.Header, e
 }
 
 func (c *Client) CreateContainer(containerName string) (http.Header, error) {
-	_, header, err := c.request("PUT", containerName, nil, 0, nil, nil)
-	return header, err
+	return c.CreateContainerWithOptions(containerName, ContainerOptions{})
 }
 
 func (c *Client) DeleteContainer(containerName string) (http.Header, error) {
@@ -366,6 +406,9 @@ type Object struct {
 	Bytes        uint
 	Name         string
 	ContentType  string `json:"content_type"`
+	// Subdir is set instead of the other fields for the pseudo-directories
+	// of a listing with a delimiter.
+	Subdir string `json:"subdir"`
 }
 
 func (c *Client) ListObjects(containerName string) ([]Object, http.Header, error) {
@@ -383,29 +426,60 @@ func (c *Client) ListObjectsWithParams(containerName string, p Params) ([]Object
 	return object, header, err
 }
 
+// ListAllObjects pages through the whole listing with p.Marker. p.Limit is
+// used as the page size.
+func (c *Client) ListAllObjects(containerName string, p Params) ([]Object, error) {
+	var objects []Object
+	for {
+		page, _, err := c.ListObjectsWithParams(containerName, p)
+		if err != nil {
+			return objects, err
+		}
+		if len(page) == 0 {
+			return objects, nil
+		}
+		objects = append(objects, page...)
+		if last := page[len(page)-1]; last.Subdir != "" {
+			p.Marker = last.Subdir
+		} else {
+			p.Marker = last.Name
+		}
+	}
+}
+
 func (c *Client) GetObject(containerName string, objectName string) ([]byte, error) {
 	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
 	resbody, _, err := c.request("GET", objectPath, nil, 0, nil, nil)
 	return resbody, err
 }
 
-func (c *Client) CreateObject(containerName string, objectName string, contentName string, metadata Metadata) (http.Header, error) {
+// GetObjectReader is like GetObject but streams the content instead of
+// reading it into memory. The caller has to close the returned body. The
+// content is returned as stored, even if it has a Content-Encoding.
+func (c *Client) GetObjectReader(containerName string, objectName string) (io.ReadCloser, http.Header, error) {
 	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
-	fi, err := os.Stat(contentName)
+	header := make(http.Header)
+	// Otherwise the transport asks for gzip and transparently decompresses
+	// gzip encoded objects, dropping their Content-Encoding.
+	header.Set("Accept-Encoding", "identity")
+	res, err := c.storageRequest("GET", objectPath, nil, 0, header, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return res.Body, res.Header, nil
+}
+
+func (c *Client) CreateObject(containerName string, objectName string, contentName string, metadata Metadata) (http.Header, error) {
+	f, err := os.Open(contentName)
 	if err != nil {
 		return nil, errors.New(fmt.Sprintf("%s", err))
 	}
-	contentLength := fi.Size()
-	b, err := ioutil.ReadFile(contentName)
+	defer f.Close()
+	fi, err := f.Stat()
 	if err != nil {
 		return nil, errors.New(fmt.Sprintf("%s", err))
 	}
-	body := bytes.NewReader(b)
-	// if c.ChunkSize != 0 {
-	// 	return nil
-	// }
-	_, header, err := c.request("PUT", objectPath, body, contentLength, http.Header(metadata), nil)
-	return header, err
+	return c.uploadObject(containerName, objectName, f, fi.Size(), fi.ModTime(), metadata)
 }
 
 func (c *Client) DeleteObject(containerName string, objectName string) error {
@@ -415,12 +489,7 @@ func (c *Client) DeleteObject(containerName string, objectName string) error {
 }
 
 func (c *Client) CopyObject(fromContainerName string, fromObjectName string, toContainerName string, toObjectName string) (http.Header, error) {
-	toObjectPath := fmt.Sprintf("%s/%s", toContainerName, toObjectName)
-	fromObjectPath := fmt.Sprintf("%s/%s", fromContainerName, fromObjectName)
-	metadata := NewMetadata()
-	metadata.SetMeta("Destination", toObjectPath)
-	_, header, err := c.request("COPY", fromObjectPath, nil, 0, http.Header(metadata), nil)
-	return header, err
+	return c.CopyObjectWithOptions(fromContainerName, fromObjectName, toContainerName, toObjectName, CopyOptions{})
 }
 
 // Objects metadata operation
@@ -458,12 +527,18 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("Swift API: got HTTP response code %d with body: %v", e.Code, e.Body)
 }
 
+func isNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == http.StatusNotFound
+}
+
 type errorReply struct {
 	Error *Error `json:"error"`
 }
 
 // CheckResponse returns an error (of type *Error) if the response
-// status code is not 2xx.
+// status code is not 2xx. A 413 from the quota middleware is returned as a
+// *QuotaExceededError wrapping the *Error, so use errors.As to get at it.
 func CheckResponse(res *http.Response) error {
 	if res.StatusCode >= 200 && res.StatusCode <= 299 {
 		return nil
@@ -480,8 +555,12 @@ func CheckResponse(res *http.Response) error {
 			return jerr.Error
 		}
 	}
-	return &Error{
+	e := &Error{
 		Code: res.StatusCode,
 		Body: string(slurp),
 	}
+	if e.Code == http.StatusRequestEntityTooLarge && strings.Contains(strings.ToLower(e.Body), "quota") {
+		return &QuotaExceededError{Err: e}
+	}
+	return e
 }
@@ -0,0 +1,213 @@
+package goswift
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccountInfo is the typed form of an account HEAD response.
+type AccountInfo struct {
+	BytesUsed      int64
+	ContainerCount int64
+	ObjectCount    int64
+	Timestamp      time.Time
+	// Policies is the usage per storage policy, keyed by the lower case
+	// policy name.
+	Policies map[string]PolicyUsage
+	Metadata UserMetadata
+	Header   http.Header
+}
+
+// ContainerInfo is the typed form of a container HEAD response.
+type ContainerInfo struct {
+	BytesUsed     int64
+	ObjectCount   int64
+	Timestamp     time.Time
+	LastModified  time.Time
+	StoragePolicy string
+	ReadACL       string
+	WriteACL      string
+	Metadata      UserMetadata
+	Header        http.Header
+}
+
+// ObjectInfo is the typed form of an object HEAD response.
+type ObjectInfo struct {
+	ContentLength     int64
+	ContentType       string
+	Etag              string
+	LastModified      time.Time
+	Timestamp         time.Time
+	DeleteAt          time.Time
+	ObjectManifest    string
+	StaticLargeObject bool
+	// Symlink is set when the object is a symlink, or was reached through
+	// one.
+	Symlink  *SymlinkTarget
+	Metadata UserMetadata
+	Header   http.Header
+}
+
+func (c *Client) StatAccount() (*AccountInfo, error) {
+	header, err := c.ShowAccountMeta()
+	if err != nil {
+		return nil, err
+	}
+	return ParseAccountInfo(header)
+}
+
+func (c *Client) StatContainer(containerName string) (*ContainerInfo, error) {
+	header, err := c.ShowContainerMeta(containerName)
+	if err != nil {
+		return nil, err
+	}
+	return ParseContainerInfo(header)
+}
+
+func (c *Client) StatObject(containerName string, objectName string) (*ObjectInfo, error) {
+	header, err := c.ShowObjectMeta(containerName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	return ParseObjectInfo(header)
+}
+
+// ParseAccountInfo builds an AccountInfo from the headers of an account HEAD.
+func ParseAccountInfo(h http.Header) (*AccountInfo, error) {
+	var err error
+	info := &AccountInfo{Header: h}
+	if info.BytesUsed, err = headerInt64(h, "X-Account-Bytes-Used"); err != nil {
+		return nil, err
+	}
+	if info.ContainerCount, err = headerInt64(h, "X-Account-Container-Count"); err != nil {
+		return nil, err
+	}
+	if info.ObjectCount, err = headerInt64(h, "X-Account-Object-Count"); err != nil {
+		return nil, err
+	}
+	if info.Timestamp, err = headerTimestamp(h, "X-Timestamp"); err != nil {
+		return nil, err
+	}
+	if info.Policies, err = parsePolicyUsage(h); err != nil {
+		return nil, err
+	}
+	info.Metadata = ParseUserMetadata(AccountScope, h)
+	return info, nil
+}
+
+// ParseContainerInfo builds a ContainerInfo from the headers of a container HEAD.
+func ParseContainerInfo(h http.Header) (*ContainerInfo, error) {
+	var err error
+	info := &ContainerInfo{
+		Header:        h,
+		StoragePolicy: h.Get("X-Storage-Policy"),
+		ReadACL:       h.Get("X-Container-Read"),
+		WriteACL:      h.Get("X-Container-Write"),
+	}
+	if info.BytesUsed, err = headerInt64(h, "X-Container-Bytes-Used"); err != nil {
+		return nil, err
+	}
+	if info.ObjectCount, err = headerInt64(h, "X-Container-Object-Count"); err != nil {
+		return nil, err
+	}
+	if info.Timestamp, err = headerTimestamp(h, "X-Timestamp"); err != nil {
+		return nil, err
+	}
+	if info.LastModified, err = headerHTTPTime(h, "Last-Modified"); err != nil {
+		return nil, err
+	}
+	info.Metadata = ParseUserMetadata(ContainerScope, h)
+	return info, nil
+}
+
+// ParseObjectInfo builds an ObjectInfo from the headers of an object HEAD.
+func ParseObjectInfo(h http.Header) (*ObjectInfo, error) {
+	var err error
+	info := &ObjectInfo{
+		Header:         h,
+		ContentType:    h.Get("Content-Type"),
+		Etag:           strings.Trim(h.Get("Etag"), `"`),
+		ObjectManifest: h.Get("X-Object-Manifest"),
+	}
+	if info.ContentLength, err = headerInt64(h, "Content-Length"); err != nil {
+		return nil, err
+	}
+	if info.LastModified, err = headerHTTPTime(h, "Last-Modified"); err != nil {
+		return nil, err
+	}
+	if info.Timestamp, err = headerTimestamp(h, "X-Timestamp"); err != nil {
+		return nil, err
+	}
+	if info.DeleteAt, err = headerTimestamp(h, "X-Delete-At"); err != nil {
+		return nil, err
+	}
+	if v := h.Get("X-Static-Large-Object"); v != "" {
+		info.StaticLargeObject, _ = strconv.ParseBool(v)
+	}
+	info.Symlink = ParseSymlinkTarget(h)
+	info.Metadata = ParseUserMetadata(ObjectScope, h)
+	return info, nil
+}
+
+func headerInt64(h http.Header, key string) (int64, error) {
+	v := h.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s header: %q", key, v)
+	}
+	return n, nil
+}
+
+// headerTimestamp parses Swift's "seconds.fraction" unix timestamps as used
+// by X-Timestamp and X-Delete-At.
+func headerTimestamp(h http.Header, key string) (time.Time, error) {
+	v := h.Get(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := parseTimestamp(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid %s header: %q", key, v)
+	}
+	return t, nil
+}
+
+func parseTimestamp(v string) (time.Time, error) {
+	sec, frac := v, ""
+	if i := strings.Index(v, "."); i >= 0 {
+		sec, frac = v[:i], v[i+1:]
+	}
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var ns int64
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		frac += strings.Repeat("0", 9-len(frac))
+		if ns, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Unix(s, ns).UTC(), nil
+}
+
+func headerHTTPTime(h http.Header, key string) (time.Time, error) {
+	v := h.Get(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid %s header: %q", key, v)
+	}
+	return t, nil
+}
@@ -0,0 +1,70 @@
+package goswift
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseObjectInfo(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Content-Length", "1024")
+	h.Set("Content-Type", "application/json")
+	h.Set("Etag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+	h.Set("Last-Modified", "Tue, 15 Nov 1994 12:45:26 GMT")
+	h.Set("X-Timestamp", "1400000000.12345")
+	h.Set("X-Delete-At", "1500000000")
+	h.Set("X-Static-Large-Object", "True")
+	h.Set("X-Object-Meta-Book", "saka01")
+	info, err := ParseObjectInfo(h)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if info.ContentLength != 1024 || info.ContentType != "application/json" {
+		t.Errorf("Expected error: %s", "Could not parse size or type.")
+	}
+	if info.Etag != "d41d8cd98f00b204e9800998ecf8427e" {
+		t.Errorf("Expected error: unexpected etag %s", info.Etag)
+	}
+	if !info.LastModified.Equal(time.Date(1994, 11, 15, 12, 45, 26, 0, time.UTC)) {
+		t.Errorf("Expected error: unexpected last modified %s", info.LastModified)
+	}
+	if !info.Timestamp.Equal(time.Unix(1400000000, 123450000)) {
+		t.Errorf("Expected error: unexpected timestamp %s", info.Timestamp)
+	}
+	if !info.DeleteAt.Equal(time.Unix(1500000000, 0)) {
+		t.Errorf("Expected error: unexpected delete at %s", info.DeleteAt)
+	}
+	if !info.StaticLargeObject {
+		t.Errorf("Expected error: %s", "Could not parse X-Static-Large-Object.")
+	}
+	if info.Metadata["book"] != "saka01" {
+		t.Errorf("Expected error: unexpected metadata %v", info.Metadata)
+	}
+}
+
+func TestParseContainerInfo(t *testing.T) {
+	h := make(http.Header)
+	h.Set("X-Container-Bytes-Used", "8464267")
+	h.Set("X-Container-Object-Count", "19")
+	h.Set("X-Storage-Policy", "gold")
+	h.Set("X-Container-Read", ".r:*,.rlistings")
+	info, err := ParseContainerInfo(h)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if info.BytesUsed != 8464267 || info.ObjectCount != 19 {
+		t.Errorf("Expected error: %s", "Could not parse usage.")
+	}
+	if info.StoragePolicy != "gold" || info.ReadACL != ".r:*,.rlistings" {
+		t.Errorf("Expected error: %s", "Could not parse policy or ACL.")
+	}
+}
+
+func TestParseAccountInfoWithInvalidHeader(t *testing.T) {
+	h := make(http.Header)
+	h.Set("X-Account-Bytes-Used", "many")
+	if _, err := ParseAccountInfo(h); err == nil {
+		t.Errorf("Expected error: %s", "Invalid header was accepted.")
+	}
+}
@@ -0,0 +1,244 @@
+package goswift
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SLOSegment is an entry of a static large object manifest as PUT with
+// ?multipart-manifest=put. Path is "/container/object".
+type SLOSegment struct {
+	Path      string `json:"path"`
+	Etag      string `json:"etag,omitempty"`
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+	Range     string `json:"range,omitempty"`
+}
+
+// SLOManifestEntry is an entry of a manifest as returned by
+// ?multipart-manifest=get. Name is "/container/object".
+type SLOManifestEntry struct {
+	Name         string `json:"name"`
+	Hash         string `json:"hash"`
+	Bytes        int64  `json:"bytes"`
+	ContentType  string `json:"content_type"`
+	LastModified string `json:"last_modified"`
+	Range        string `json:"range"`
+	SubSLO       bool   `json:"sub_slo"`
+}
+
+// Segment returns the entry in the form CreateSLOManifest expects.
+func (e SLOManifestEntry) Segment() SLOSegment {
+	return SLOSegment{Path: e.Name, Etag: e.Hash, SizeBytes: e.Bytes, Range: e.Range}
+}
+
+// singlePutSize is the size up to which objects are always uploaded with a
+// single PUT. Clusters rarely lower max_file_size this far.
+const singlePutSize = 1 << 30
+
+// SegmentContainer returns the container segments of objects in
+// containerName are uploaded to, following python-swiftclient.
+func SegmentContainer(containerName string) string {
+	return containerName + "_segments"
+}
+
+// needsSegments reports whether an object of size bytes has to be uploaded
+// as a large object, either because it exceeds c.ChunkSize or the cluster's
+// max_file_size. Sizes well below Swift's default max_file_size are sent
+// in one PUT without consulting /info.
+func (c *Client) needsSegments(size int64) bool {
+	if c.ChunkSize != 0 && size > int64(c.ChunkSize) {
+		return true
+	}
+	if size <= singlePutSize {
+		return false
+	}
+	return size > c.maxFileSize()
+}
+
+func (c *Client) maxFileSize() int64 {
+	if n := c.capabilities().Swift.MaxFileSize; n > 0 {
+		return n
+	}
+	return defaultMaxFileSize
+}
+
+// segmentSize picks the segment size for an object of size bytes. It starts
+// from c.ChunkSize and adjusts it to the cluster's min_segment_size,
+// max_manifest_segments and max_file_size.
+func (c *Client) segmentSize(size int64) (int64, error) {
+	caps := c.capabilities()
+	maxFile := c.maxFileSize()
+	seg := int64(c.ChunkSize)
+	if seg == 0 || seg > maxFile {
+		seg = maxFile
+	}
+	maxSegments := int64(defaultMaxManifestSegments)
+	if caps.SLO != nil {
+		if caps.SLO.MinSegmentSize > seg {
+			seg = caps.SLO.MinSegmentSize
+		}
+		if caps.SLO.MaxManifestSegments > 0 {
+			maxSegments = int64(caps.SLO.MaxManifestSegments)
+		}
+	}
+	if (size+seg-1)/seg > maxSegments {
+		seg = (size + maxSegments - 1) / maxSegments
+	}
+	if seg > maxFile {
+		return 0, fmt.Errorf("Object of %d bytes exceeds the cluster's large object limits", size)
+	}
+	return seg, nil
+}
+
+// useDLO reports whether large objects have to fall back to dynamic large
+// object manifests because the cluster publishes /info without SLO support.
+func (c *Client) useDLO() bool {
+	caps := c.capabilities()
+	return len(caps.Raw) != 0 && caps.SLO == nil
+}
+
+// CreateSLOManifest writes a static large object manifest referencing
+// segments.
+func (c *Client) CreateSLOManifest(containerName string, objectName string, segments []SLOSegment, metadata Metadata) (http.Header, error) {
+	return c.createSLOManifest(context.Background(), containerName, objectName, segments, metadata)
+}
+
+func (c *Client) createSLOManifest(ctx context.Context, containerName string, objectName string, segments []SLOSegment, metadata Metadata) (http.Header, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	b, err := json.Marshal(segments)
+	if err != nil {
+		return nil, err
+	}
+	params := make(url.Values)
+	params.Set("multipart-manifest", "put")
+	_, header, err := c.requestContext(ctx, "PUT", objectPath, bytes.NewReader(b), int64(len(b)), http.Header(metadata), params)
+	return header, err
+}
+
+// GetSLOManifest returns the segments of a static large object rather than
+// its content.
+func (c *Client) GetSLOManifest(containerName string, objectName string) ([]SLOManifestEntry, http.Header, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	params := make(url.Values)
+	params.Set("multipart-manifest", "get")
+	body, header, err := c.request("GET", objectPath, nil, 0, nil, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	var entries []SLOManifestEntry
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, header, errors.New(fmt.Sprintf("%s is not a static large object: %s", objectPath, err))
+	}
+	return entries, header, nil
+}
+
+// CreateDLOManifest writes a dynamic large object manifest concatenating
+// every object of segmentContainer starting with prefix.
+func (c *Client) CreateDLOManifest(containerName string, objectName string, segmentContainer string, prefix string, metadata Metadata) (http.Header, error) {
+	return c.createDLOManifest(context.Background(), containerName, objectName, segmentContainer, prefix, metadata)
+}
+
+func (c *Client) createDLOManifest(ctx context.Context, containerName string, objectName string, segmentContainer string, prefix string, metadata Metadata) (http.Header, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	h := http.Header{}
+	for k, v := range metadata {
+		h[k] = v
+	}
+	h.Set("X-Object-Manifest", fmt.Sprintf("%s/%s", segmentContainer, prefix))
+	_, header, err := c.requestContext(ctx, "PUT", objectPath, nil, 0, h, nil)
+	return header, err
+}
+
+// putSegment uploads one segment and checks its ETag against the MD5 of the
+// data sent.
+func (c *Client) putSegment(ctx context.Context, segmentContainer string, segmentName string, body io.Reader, size int64) (SLOSegment, error) {
+	objectPath := fmt.Sprintf("%s/%s", segmentContainer, segmentName)
+	hash := md5.New()
+	_, header, err := c.requestContext(ctx, "PUT", objectPath, io.TeeReader(body, hash), size, nil, nil)
+	if err != nil {
+		return SLOSegment{}, err
+	}
+	etag := hex.EncodeToString(hash.Sum(nil))
+	if got := strings.Trim(http.Header(header).Get("Etag"), `"`); got != "" && got != etag {
+		return SLOSegment{}, errors.New(fmt.Sprintf("Segment %s was corrupted in transit: etag %s, expected %s", objectPath, got, etag))
+	}
+	return SLOSegment{Path: "/" + objectPath, Etag: etag, SizeBytes: size}, nil
+}
+
+// segmentPrefix names the segments of an upload the way python-swiftclient
+// does, so tools can recognise them.
+func segmentPrefix(objectName string, stamp time.Time, size int64, segSize int64) string {
+	return fmt.Sprintf("%s/slo/%d.%06d/%d/%d/", objectName, stamp.Unix(), stamp.Nanosecond()/1000, size, segSize)
+}
+
+// uploadObject uploads size bytes of r with a single PUT, or as a large
+// object if it is too large for one.
+func (c *Client) uploadObject(containerName string, objectName string, r io.ReaderAt, size int64, stamp time.Time, metadata Metadata) (http.Header, error) {
+	if c.needsSegments(size) {
+		return c.createLargeObject(containerName, objectName, r, size, stamp, metadata)
+	}
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	var body io.Reader
+	if size > 0 {
+		// a zero length with a body would be sent chunked
+		body = io.NewSectionReader(r, 0, size)
+	}
+	_, header, err := c.request("PUT", objectPath, body, size, http.Header(metadata), nil)
+	return header, err
+}
+
+// createLargeObject uploads r in segments to the segment container and
+// writes the manifest. Uploaded segments are removed again on failure.
+func (c *Client) createLargeObject(containerName string, objectName string, r io.ReaderAt, size int64, stamp time.Time, metadata Metadata) (http.Header, error) {
+	segSize, err := c.segmentSize(size)
+	if err != nil {
+		return nil, err
+	}
+	segContainer := SegmentContainer(containerName)
+	if _, err := c.CreateContainer(segContainer); err != nil {
+		return nil, err
+	}
+	prefix := segmentPrefix(objectName, stamp, size, segSize)
+	var segments []SLOSegment
+	for i, offset := 0, int64(0); offset < size; i, offset = i+1, offset+segSize {
+		n := segSize
+		if size-offset < n {
+			n = size - offset
+		}
+		segName := fmt.Sprintf("%s%08d", prefix, i)
+		segment, err := c.putSegment(context.Background(), segContainer, segName, io.NewSectionReader(r, offset, n), n)
+		if err != nil {
+			c.deleteSegments(segments)
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+	var header http.Header
+	if c.useDLO() {
+		header, err = c.CreateDLOManifest(containerName, objectName, segContainer, prefix, metadata)
+	} else {
+		header, err = c.CreateSLOManifest(containerName, objectName, segments, metadata)
+	}
+	if err != nil {
+		c.deleteSegments(segments)
+		return nil, err
+	}
+	return header, nil
+}
+
+// deleteSegments removes uploaded segments on a best effort basis.
+func (c *Client) deleteSegments(segments []SLOSegment) {
+	for _, s := range segments {
+		c.request("DELETE", strings.TrimPrefix(s.Path, "/"), nil, 0, nil, nil)
+	}
+}
@@ -0,0 +1,261 @@
+package goswift
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// MarshalMetadata converts the exported fields of the struct v to user
+// metadata headers of scope. The key of each field is taken from its
+// `swift:"name"` tag, or the field name if there is none. The "omitempty"
+// option skips zero values and a tag of "-" skips the field.
+//
+// Supported field types are strings, bools, ints, uints, floats, time.Time
+// (as RFC 3339) and slices of those (comma separated).
+//
+//	type Attrs struct {
+//		Owner   string    `swift:"owner-id"`
+//		Version int       `swift:"schema-version"`
+//		Tags    []string  `swift:"tags,omitempty"`
+//	}
+//	metadata, err := MarshalMetadata(ObjectScope, Attrs{...})
+func MarshalMetadata(scope MetaScope, v interface{}) (Metadata, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.New("MarshalMetadata: nil pointer")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("MarshalMetadata: unsupported type %s", rv.Type())
+	}
+	u := make(UserMetadata)
+	if err := marshalStruct(u, rv); err != nil {
+		return nil, err
+	}
+	return u.Metadata(scope)
+}
+
+// UnmarshalMetadata stores the user metadata of scope found in metadata into
+// the struct pointed to by v. Fields are matched as in MarshalMetadata and
+// fields without a matching header are left untouched.
+func UnmarshalMetadata(scope MetaScope, metadata Metadata, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("UnmarshalMetadata: v must be a non-nil pointer to a struct")
+	}
+	return unmarshalStruct(ParseUserMetadata(scope, http.Header(metadata)), rv.Elem())
+}
+
+type metaField struct {
+	name      string
+	omitEmpty bool
+}
+
+func parseMetaTag(f reflect.StructField) (metaField, bool) {
+	tag := f.Tag.Get("swift")
+	if tag == "-" {
+		return metaField{}, false
+	}
+	opts := strings.Split(tag, ",")
+	mf := metaField{name: opts[0]}
+	if mf.name == "" {
+		mf.name = f.Name
+	}
+	for _, o := range opts[1:] {
+		if o == "omitempty" {
+			mf.omitEmpty = true
+		}
+	}
+	return mf, true
+}
+
+func marshalStruct(u UserMetadata, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		fv := rv.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("swift") == "" {
+			if err := marshalStruct(u, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		mf, ok := parseMetaTag(f)
+		if !ok {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if mf.omitEmpty && isEmptyMetaValue(fv) {
+			continue
+		}
+		s, err := formatMetaValue(fv)
+		if err != nil {
+			return fmt.Errorf("MarshalMetadata: field %s: %s", f.Name, err)
+		}
+		u.Set(mf.name, s)
+	}
+	return nil
+}
+
+func unmarshalStruct(u UserMetadata, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		fv := rv.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("swift") == "" {
+			if err := unmarshalStruct(u, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		mf, ok := parseMetaTag(f)
+		if !ok {
+			continue
+		}
+		s, ok := u[foldMetaKey(mf.name)]
+		if !ok {
+			continue
+		}
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		if err := parseMetaValue(fv, s); err != nil {
+			return fmt.Errorf("UnmarshalMetadata: field %s: %s", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func isEmptyMetaValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+	return false
+}
+
+func formatMetaValue(v reflect.Value) (string, error) {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Slice {
+			break
+		}
+		items := make([]string, v.Len())
+		for i := range items {
+			s, err := formatMetaValue(v.Index(i))
+			if err != nil {
+				return "", err
+			}
+			if strings.Contains(s, ",") {
+				return "", fmt.Errorf("slice item %q contains a comma", s)
+			}
+			items[i] = s
+		}
+		return strings.Join(items, ","), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func parseMetaValue(v reflect.Value, s string) error {
+	if v.Type() == timeType {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Slice {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items []string
+		if s != "" {
+			items = strings.Split(s, ",")
+		}
+		sl := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := parseMetaValue(sl.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(sl)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package goswift
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type objectAttrs struct {
+	Owner     string    `swift:"owner-id"`
+	Version   int       `swift:"schema-version"`
+	Checksum  string    `swift:"checksum-algorithm,omitempty"`
+	Verified  bool      `swift:"verified"`
+	Created   time.Time `swift:"created"`
+	Tags      []string  `swift:"tags"`
+	Sizes     []int64   `swift:"sizes,omitempty"`
+	Ignored   string    `swift:"-"`
+	unexposed string
+}
+
+func TestMarshalMetadata(t *testing.T) {
+	created := time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)
+	in := objectAttrs{Owner: "ton-katsu", Version: 3, Verified: true, Created: created, Tags: []string{"a", "b"}, Ignored: "x"}
+	metadata, err := MarshalMetadata(ObjectScope, &in)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	h := http.Header(metadata)
+	if h.Get("X-Object-Meta-Owner-Id") != "ton-katsu" || h.Get("X-Object-Meta-Schema-Version") != "3" {
+		t.Errorf("Expected error: unexpected headers %v", h)
+	}
+	if h.Get("X-Object-Meta-Tags") != "a,b" || h.Get("X-Object-Meta-Created") != "2015-03-01T12:00:00Z" {
+		t.Errorf("Expected error: unexpected headers %v", h)
+	}
+	if _, ok := h["X-Object-Meta-Checksum-Algorithm"]; ok {
+		t.Errorf("Expected error: %s", "omitempty field was marshalled.")
+	}
+	if _, ok := h["X-Object-Meta-Ignored"]; ok {
+		t.Errorf("Expected error: %s", "Ignored field was marshalled.")
+	}
+
+	var out objectAttrs
+	if err := UnmarshalMetadata(ObjectScope, metadata, &out); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	in.Ignored = ""
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Expected error: got %+v, want %+v", out, in)
+	}
+}
+
+func TestUnmarshalMetadataWithInvalidValue(t *testing.T) {
+	metadata := NewMetadata()
+	metadata.SetMeta("X-Container-Meta-Schema-Version", "three")
+	var out objectAttrs
+	if err := UnmarshalMetadata(ContainerScope, metadata, &out); err == nil {
+		t.Errorf("Expected error: %s", "Invalid int was accepted.")
+	}
+	if err := UnmarshalMetadata(ContainerScope, metadata, out); err == nil {
+		t.Errorf("Expected error: %s", "Non-pointer was accepted.")
+	}
+}
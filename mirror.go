@@ -0,0 +1,475 @@
+package goswift
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MirrorOptions controls MirrorAccount and MirrorContainer.
+type MirrorOptions struct {
+	// Concurrency is the number of objects copied at once. Defaults to 8.
+	Concurrency int
+	// Checkpoint is a file progress is saved to. A mirror interrupted and
+	// started again with the same checkpoint skips the containers and
+	// listing pages it completed.
+	Checkpoint string
+}
+
+// MirrorReport lists the objects a mirror copied and those that were
+// already up to date, as "container/object".
+type MirrorReport struct {
+	Copied    []string
+	Unchanged []string
+	Failed    []ObjectError
+}
+
+// MirrorAccount copies every container of the account to the account of
+// dst, which may be on another cluster. See MirrorContainer for what is
+// copied. Account metadata and the account ACL are copied as well, except
+// the quota only a reseller admin can set.
+func (c *Client) MirrorAccount(dst *Client, opts MirrorOptions) (*MirrorReport, error) {
+	m, err := newMirror(c, dst, opts)
+	if err != nil {
+		return nil, err
+	}
+	header, err := c.ShowAccountMeta()
+	if err != nil {
+		return nil, err
+	}
+	if metadata := preservedAccountMetadata(header); len(metadata) != 0 {
+		if _, err := dst.CreateAccountMeta(metadata); err != nil {
+			return nil, err
+		}
+	}
+	marker := ""
+	for {
+		containers, _, err := c.ListContainersWithParams(Params{Marker: marker})
+		if err != nil {
+			return m.finish(err)
+		}
+		if len(containers) == 0 {
+			break
+		}
+		for _, container := range containers {
+			m.container(container.Name, container.Name)
+		}
+		marker = containers[len(containers)-1].Name
+	}
+	return m.finish(nil)
+}
+
+// MirrorContainer copies the objects of containerName to dstContainerName
+// of dst, which may be on another cluster, creating it with the metadata,
+// ACLs and storage policy of the source if needed.
+//
+// Objects are compared by their listing ETag and size and streamed from
+// source to destination without buffering. Object metadata and content
+// types are preserved. Large objects keep their structure: their segments
+// are mirrored to the same segment containers and a new manifest is
+// written. A dynamic large object whose manifest is unchanged is not
+// checked for changed segments.
+func (c *Client) MirrorContainer(containerName string, dst *Client, dstContainerName string, opts MirrorOptions) (*MirrorReport, error) {
+	m, err := newMirror(c, dst, opts)
+	if err != nil {
+		return nil, err
+	}
+	m.container(containerName, dstContainerName)
+	return m.finish(nil)
+}
+
+type mirror struct {
+	src        *Client
+	dst        *Client
+	opts       MirrorOptions
+	checkpoint *mirrorCheckpoint
+
+	mu         sync.Mutex
+	report     MirrorReport
+	containers map[string]bool
+}
+
+func newMirror(src *Client, dst *Client, opts MirrorOptions) (*mirror, error) {
+	if err := src.prepare(); err != nil {
+		return nil, err
+	}
+	if err := dst.prepare(); err != nil {
+		return nil, err
+	}
+	src.capabilities()
+	dst.capabilities()
+	checkpoint, err := loadMirrorCheckpoint(opts.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &mirror{src: src, dst: dst, opts: opts, checkpoint: checkpoint, containers: make(map[string]bool)}, nil
+}
+
+func (m *mirror) fail(name string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.report.Failed = append(m.report.Failed, ObjectError{Name: name, Err: err})
+}
+
+func (m *mirror) finish(err error) (*MirrorReport, error) {
+	r := &m.report
+	sort.Strings(r.Copied)
+	sort.Strings(r.Unchanged)
+	sort.Slice(r.Failed, func(i, j int) bool { return r.Failed[i].Name < r.Failed[j].Name })
+	if err == nil && len(r.Failed) != 0 {
+		err = fmt.Errorf("Could not mirror %d objects.", len(r.Failed))
+	}
+	return r, err
+}
+
+// container mirrors one container page by page. The checkpoint only moves
+// past pages without failures, so failed objects are retried on resume.
+func (m *mirror) container(srcName string, dstName string) {
+	key := srcName + "/" + dstName
+	if m.checkpoint.done(key) {
+		return
+	}
+	if err := m.setupContainer(srcName, dstName); err != nil {
+		m.fail(srcName, err)
+		return
+	}
+	existing, err := m.dst.ListAllObjects(dstName, Params{})
+	if err != nil {
+		m.fail(srcName, err)
+		return
+	}
+	dstObjects := make(map[string]Object, len(existing))
+	for _, o := range existing {
+		dstObjects[o.Name] = o
+	}
+
+	marker := m.checkpoint.marker(key)
+	clean := true
+	for {
+		page, _, err := m.src.ListObjectsWithParams(srcName, Params{Marker: marker})
+		if err != nil {
+			m.fail(srcName, err)
+			return
+		}
+		if len(page) == 0 {
+			break
+		}
+		pageClean := true
+		parallel(m.opts.Concurrency, len(page), func(i int) {
+			o := page[i]
+			name := srcName + "/" + o.Name
+			d, ok := dstObjects[o.Name]
+			if ok && d.Hash == o.Hash && d.Bytes == o.Bytes {
+				m.mu.Lock()
+				m.report.Unchanged = append(m.report.Unchanged, name)
+				m.mu.Unlock()
+				return
+			}
+			err := m.copy(srcName, o.Name, dstName, o.Name)
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if err != nil {
+				m.report.Failed = append(m.report.Failed, ObjectError{Name: name, Err: err})
+				pageClean = false
+			} else {
+				m.report.Copied = append(m.report.Copied, name)
+			}
+		})
+		marker = page[len(page)-1].Name
+		clean = clean && pageClean
+		if clean {
+			if err := m.checkpoint.setMarker(key, marker); err != nil {
+				m.fail(srcName, err)
+				return
+			}
+		}
+	}
+	if clean {
+		if err := m.checkpoint.setDone(key); err != nil {
+			m.fail(srcName, err)
+		}
+	}
+}
+
+// setupContainer creates the destination container with the source's
+// storage policy, if the destination cluster has it, or updates the
+// metadata of an existing one. The policy of an existing container cannot
+// be changed.
+func (m *mirror) setupContainer(srcName string, dstName string) error {
+	m.mu.Lock()
+	done := m.containers[dstName]
+	m.mu.Unlock()
+	if done {
+		return nil
+	}
+	if err := m.createContainer(srcName, dstName); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.containers[dstName] = true
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *mirror) createContainer(srcName string, dstName string) error {
+	header, err := m.src.ShowContainerMeta(srcName)
+	if err != nil {
+		return err
+	}
+	metadata := preservedContainerMetadata(header)
+	if _, err := m.dst.ShowContainerMeta(dstName); err == nil {
+		if len(metadata) != 0 {
+			_, err = m.dst.CreateContainerMeta(dstName, metadata)
+		}
+		return err
+	} else if !isNotFound(err) {
+		return err
+	}
+	opts := ContainerOptions{Metadata: metadata}
+	if policy := header.Get("X-Storage-Policy"); policy != "" && m.dstHasPolicy(policy) {
+		opts.StoragePolicy = policy
+	}
+	_, err = m.dst.CreateContainerWithOptions(dstName, opts)
+	return err
+}
+
+func (m *mirror) dstHasPolicy(name string) bool {
+	for _, p := range m.dst.capabilities().Swift.Policies {
+		if strings.EqualFold(p.Name, name) {
+			return true
+		}
+		for _, alias := range strings.Split(p.Aliases, ",") {
+			if strings.EqualFold(strings.TrimSpace(alias), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copy copies one object. Manifests are rewritten after their segments
+// were mirrored; other objects are streamed.
+func (m *mirror) copy(srcContainer string, srcName string, dstContainer string, dstName string) error {
+	header, err := m.src.ShowObjectMeta(srcContainer, srcName)
+	if err != nil {
+		return err
+	}
+	metadata := preservedMetadata(header)
+	if manifest := header.Get("X-Object-Manifest"); manifest != "" {
+		parts := strings.SplitN(manifest, "/", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("Invalid X-Object-Manifest: %s", manifest)
+		}
+		segments, err := m.src.ListAllObjects(parts[0], Params{Prefix: parts[1]})
+		if err != nil {
+			return err
+		}
+		for _, s := range segments {
+			// The prefix may match the manifest itself.
+			if parts[0] == srcContainer && s.Name == srcName {
+				continue
+			}
+			if err := m.segment(parts[0], s.Name, false); err != nil {
+				return err
+			}
+		}
+		_, err = m.dst.CreateDLOManifest(dstContainer, dstName, parts[0], parts[1], metadata)
+		return err
+	}
+	if isManifest(header) {
+		entries, _, err := m.src.GetSLOManifest(srcContainer, srcName)
+		if err != nil {
+			return err
+		}
+		segments := make([]SLOSegment, len(entries))
+		for i, e := range entries {
+			parts := strings.SplitN(strings.TrimPrefix(e.Name, "/"), "/", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("Invalid segment path: %s", e.Name)
+			}
+			if err := m.segment(parts[0], parts[1], true); err != nil {
+				return err
+			}
+			segments[i] = e.Segment()
+		}
+		_, err = m.dst.CreateSLOManifest(dstContainer, dstName, segments, metadata)
+		return err
+	}
+
+	// The body is streamed as stored, so the source's Content-Length, Etag
+	// and Content-Encoding describe what is sent.
+	body, header, err := m.src.GetObjectReader(srcContainer, srcName)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	length := int64(-1)
+	if header.Get("Content-Length") != "" {
+		if length, err = headerInt64(header, "Content-Length"); err != nil {
+			return err
+		}
+	}
+	metadata = preservedMetadata(header)
+	if etag := strings.Trim(header.Get("Etag"), `"`); etag != "" {
+		metadata.SetMeta("Etag", etag)
+	}
+	objectPath := fmt.Sprintf("%s/%s", dstContainer, dstName)
+	_, _, err = m.dst.request("PUT", objectPath, body, length, http.Header(metadata), nil)
+	return err
+}
+
+// segment mirrors a large object segment to the same path unless the
+// destination already has it with the same ETag. Swift serves DLO segments
+// as stored, so for them followDLO is false and a segment that is itself a
+// DLO manifest is mirrored without its segments.
+func (m *mirror) segment(containerName string, objectName string, followDLO bool) error {
+	if err := m.setupContainer(containerName, containerName); err != nil {
+		return err
+	}
+	srcHeader, err := m.src.ShowObjectMeta(containerName, objectName)
+	if err != nil {
+		return err
+	}
+	dstHeader, err := m.dst.ShowObjectMeta(containerName, objectName)
+	if err == nil && dstHeader.Get("Etag") == srcHeader.Get("Etag") {
+		return nil
+	}
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if manifest := srcHeader.Get("X-Object-Manifest"); manifest != "" && !followDLO {
+		parts := strings.SplitN(manifest, "/", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("Invalid X-Object-Manifest: %s", manifest)
+		}
+		_, err = m.dst.CreateDLOManifest(containerName, objectName, parts[0], parts[1], preservedMetadata(srcHeader))
+		return err
+	}
+	return m.copy(containerName, objectName, containerName, objectName)
+}
+
+// preservedContainerMetadata picks the headers of a container HEAD that a
+// mirror of the container should have. Container sync settings are left
+// out, as the mirror would sync to the same peer.
+func preservedContainerMetadata(h http.Header) Metadata {
+	metadata := NewMetadata()
+	for k, v := range h {
+		k = http.CanonicalHeaderKey(k)
+		switch {
+		case strings.HasPrefix(k, "X-Container-Meta-"),
+			k == "X-Container-Read", k == "X-Container-Write",
+			k == "X-Versions-Location", k == "X-History-Location", k == "X-Versions-Enabled":
+			metadata.SetMeta(k, v[0])
+		}
+	}
+	return metadata
+}
+
+// preservedAccountMetadata picks the headers of an account HEAD that the
+// account owner can set on the mirror.
+func preservedAccountMetadata(h http.Header) Metadata {
+	metadata := NewMetadata()
+	for k, v := range h {
+		k = http.CanonicalHeaderKey(k)
+		switch {
+		case k == "X-Account-Meta-Quota-Bytes":
+		case strings.HasPrefix(k, "X-Account-Meta-"), k == "X-Account-Access-Control":
+			metadata.SetMeta(k, v[0])
+		}
+	}
+	return metadata
+}
+
+// mirrorCheckpoint records the progress of a mirror in a JSON file. A
+// checkpoint without a path is kept in memory only.
+type mirrorCheckpoint struct {
+	path string
+	mu   sync.Mutex
+	// Markers holds the last object of the last completed listing page of
+	// every "source/destination" container pair.
+	Markers map[string]string `json:"markers"`
+	// Done holds the container pairs that were mirrored completely.
+	Done map[string]bool `json:"done"`
+}
+
+func loadMirrorCheckpoint(path string) (*mirrorCheckpoint, error) {
+	cp := &mirrorCheckpoint{path: path, Markers: make(map[string]string), Done: make(map[string]bool)}
+	if path == "" {
+		return cp, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, cp); err != nil {
+		return nil, fmt.Errorf("Invalid mirror checkpoint %s: %s", path, err)
+	}
+	if cp.Markers == nil {
+		cp.Markers = make(map[string]string)
+	}
+	if cp.Done == nil {
+		cp.Done = make(map[string]bool)
+	}
+	return cp, nil
+}
+
+func (cp *mirrorCheckpoint) marker(key string) string {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.Markers[key]
+}
+
+func (cp *mirrorCheckpoint) done(key string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.Done[key]
+}
+
+func (cp *mirrorCheckpoint) setMarker(key string, marker string) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.Markers[key] = marker
+	return cp.save()
+}
+
+func (cp *mirrorCheckpoint) setDone(key string) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	delete(cp.Markers, key)
+	cp.Done[key] = true
+	return cp.save()
+}
+
+// save writes the checkpoint to a temporary file first, so an interrupted
+// write does not lose the previous checkpoint.
+func (cp *mirrorCheckpoint) save() error {
+	if cp.path == "" {
+		return nil
+	}
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(cp.path), ".goswift-checkpoint-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), cp.path)
+}
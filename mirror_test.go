@@ -0,0 +1,194 @@
+package goswift
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPreservedContainerMetadata(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Container-Meta-Web-Index", "index.html")
+	h.Set("X-Container-Read", ".r:*")
+	h.Set("X-Versions-Location", "archive")
+	h.Set("X-Container-Sync-To", "//realm/cluster/AUTH_a/c")
+	h.Set("X-Container-Object-Count", "3")
+	metadata := http.Header(preservedContainerMetadata(h))
+	if len(metadata) != 3 || metadata.Get("X-Container-Read") != ".r:*" || metadata.Get("X-Container-Sync-To") != "" {
+		t.Errorf("Expected error: unexpected metadata %v", metadata)
+	}
+}
+
+func TestPreservedAccountMetadata(t *testing.T) {
+	h := http.Header{}
+	h.Set("X-Account-Meta-Temp-Url-Key", "secret")
+	h.Set("X-Account-Meta-Quota-Bytes", "1000")
+	h.Set("X-Account-Access-Control", `{"read-only":["alice"]}`)
+	h.Set("X-Account-Bytes-Used", "10")
+	metadata := http.Header(preservedAccountMetadata(h))
+	if len(metadata) != 2 || metadata.Get("X-Account-Meta-Quota-Bytes") != "" {
+		t.Errorf("Expected error: unexpected metadata %v", metadata)
+	}
+}
+
+func TestMirrorCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goswift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+	cp, err := loadMirrorCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if err := cp.setMarker("a/a", "photos/0999.jpg"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if err := cp.setDone("b/b"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	cp, err = loadMirrorCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if cp.marker("a/a") != "photos/0999.jpg" || !cp.done("b/b") || cp.done("a/a") {
+		t.Errorf("Expected error: checkpoint was not restored: %+v", cp)
+	}
+
+	ioutil.WriteFile(path, []byte("{"), 0644)
+	if _, err := loadMirrorCheckpoint(path); err == nil {
+		t.Errorf("Expected error: invalid checkpoint was loaded")
+	}
+}
+
+func TestMirrorContainer(t *testing.T) {
+	src, c := newFakeSwift(t)
+	dst, d := newFakeSwift(t)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte("hello world"))
+	zw.Close()
+	src.putObject("c", "log.gz", buf.Bytes(), http.Header{"Content-Encoding": {"gzip"}, "X-Object-Meta-Host": {"web1"}})
+	src.putObject("c_segments", "dlo/1", []byte("hello "), nil)
+	src.putObject("c_segments", "dlo/2", []byte("dlo"), nil)
+	src.putObject("c", "dlo", nil, http.Header{"X-Object-Manifest": {"c_segments/dlo/"}})
+	src.putObject("c_segments", "slo/1", []byte("hello "), nil)
+	src.putObject("c_segments", "slo/2", []byte("slo"), nil)
+	src.putObject("c", "slo", nil, http.Header{"X-Static-Large-Object": {"True"}, "Content-Type": {"text/plain"}})
+	src.object("c", "slo").manifest = []SLOSegment{
+		{Path: "/c_segments/slo/1", Etag: md5Hex([]byte("hello ")), SizeBytes: 6},
+		{Path: "/c_segments/slo/2", Etag: md5Hex([]byte("slo")), SizeBytes: 3},
+	}
+
+	report, err := c.MirrorContainer("c", d, "c", MirrorOptions{})
+	if err != nil {
+		t.Fatalf("Expected error: %s %+v", err, report)
+	}
+	if len(report.Copied) != 3 {
+		t.Errorf("Expected error: unexpected report %+v", report)
+	}
+	o := dst.object("c", "log.gz")
+	if o == nil || !bytes.Equal(o.data, buf.Bytes()) || o.header.Get("Content-Encoding") != "gzip" || o.header.Get("X-Object-Meta-Host") != "web1" {
+		t.Errorf("Expected error: %s", "Gzip encoded object was not copied as stored.")
+	}
+	for _, r := range dst.recorded("PUT") {
+		if r.Path == "/v1/AUTH_test/c/log.gz" && r.ContentLength != int64(buf.Len()) {
+			t.Errorf("Expected error: object was sent with length %d", r.ContentLength)
+		}
+	}
+	if o := dst.object("c", "dlo"); o == nil || o.header.Get("X-Object-Manifest") != "c_segments/dlo/" {
+		t.Errorf("Expected error: %s", "DLO manifest was not written.")
+	}
+	if o := dst.object("c", "slo"); o == nil || len(o.manifest) != 2 || o.header.Get("Content-Type") != "text/plain" {
+		t.Errorf("Expected error: %s", "SLO manifest was not written.")
+	}
+	for name, content := range map[string]string{"dlo": "hello dlo", "slo": "hello slo"} {
+		body, _, err := d.GetObjectReader("c", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(body)
+		body.Close()
+		if string(b) != content {
+			t.Errorf("Expected error: %s has content %q", name, b)
+		}
+	}
+
+	report, err = c.MirrorContainer("c", d, "c", MirrorOptions{})
+	if err != nil || len(report.Copied) != 0 || len(report.Unchanged) != 3 {
+		t.Errorf("Expected error: unexpected second report %+v, %v", report, err)
+	}
+}
+
+func TestMirrorSelfReferencingDLO(t *testing.T) {
+	src, c := newFakeSwift(t)
+	_, d := newFakeSwift(t)
+	src.putObject("c", "big/001", []byte("hello "), nil)
+	src.putObject("c", "big/002", []byte("dlo"), nil)
+	src.putObject("c", "big", nil, http.Header{"X-Object-Manifest": {"c/big"}})
+	// a DLO segment that is itself a manifest is not followed
+	src.putObject("c", "big/003", nil, http.Header{"X-Object-Manifest": {"c/big"}})
+
+	report, err := c.MirrorContainer("c", d, "c", MirrorOptions{})
+	if err != nil {
+		t.Fatalf("Expected error: %s %+v", err, report)
+	}
+	body, _, err := d.GetObjectReader("c", "big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(body)
+	body.Close()
+	if string(b) != "hello dlo" {
+		t.Errorf("Expected error: big has content %q", b)
+	}
+}
+
+func TestMirrorResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goswift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opts := MirrorOptions{Checkpoint: filepath.Join(dir, "checkpoint.json")}
+	src, c := newFakeSwift(t)
+	dst, d := newFakeSwift(t)
+	src.listingLimit = 2
+	for _, name := range []string{"a", "b", "c", "d"} {
+		src.putObject("photos", name, []byte(name), nil)
+	}
+	dst.fail = func(r *http.Request) int {
+		if r.Method == "PUT" && r.URL.Path == "/v1/AUTH_test/photos/c" {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}
+	report, err := c.MirrorAccount(d, opts)
+	if err == nil || report == nil || fmt.Sprint(report.Copied) != "[photos/a photos/b photos/d]" || len(report.Failed) != 1 {
+		t.Fatalf("Expected error: unexpected report %+v, %v", report, err)
+	}
+
+	// Resuming skips the first page, so a change there is not picked up.
+	src.putObject("photos", "a", []byte("changed"), nil)
+	dst.mu.Lock()
+	dst.fail = nil
+	dst.mu.Unlock()
+	report, err = c.MirrorAccount(d, opts)
+	if err != nil || fmt.Sprint(report.Copied, report.Unchanged) != "[photos/c] [photos/d]" {
+		t.Errorf("Expected error: unexpected resumed report %+v, %v", report, err)
+	}
+	if o := dst.object("photos", "a"); o == nil || string(o.data) != "a" {
+		t.Errorf("Expected error: %s", "Completed page was mirrored again.")
+	}
+
+	report, err = c.MirrorAccount(d, opts)
+	if err != nil || len(report.Copied)+len(report.Unchanged) != 0 {
+		t.Errorf("Expected error: completed container was mirrored again: %+v, %v", report, err)
+	}
+}
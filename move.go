@@ -0,0 +1,281 @@
+package goswift
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const defaultConcurrency = 8
+
+// parallel calls fn for every index below count on at most n goroutines.
+func parallel(n int, count int, fn func(i int)) {
+	if n <= 0 {
+		n = defaultConcurrency
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < n && w < count; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// prepare authenticates up front so concurrent workers share the token.
+func (c *Client) prepare() error {
+	c.setClient()
+	return c.setCredential()
+}
+
+// preservedMetadata picks the headers of an object HEAD that describe the
+// object and have to be sent again when it is rewritten. A POST drops
+// every one of them that is not sent, including the X-Object-Manifest of a
+// dynamic large object.
+func preservedMetadata(h http.Header) Metadata {
+	metadata := NewMetadata()
+	for k, v := range h {
+		k = http.CanonicalHeaderKey(k)
+		switch {
+		case strings.HasPrefix(k, "X-Object-Meta-"),
+			k == "Content-Type", k == "Content-Disposition",
+			k == "Content-Encoding", k == "Content-Language",
+			k == "Cache-Control", k == "Expires", k == "X-Robots-Tag",
+			k == "X-Delete-At", k == "X-Object-Manifest":
+			metadata.SetMeta(k, v[0])
+		}
+	}
+	return metadata
+}
+
+func isManifest(h http.Header) bool {
+	return h.Get("X-Object-Manifest") != "" || strings.EqualFold(h.Get("X-Static-Large-Object"), "true")
+}
+
+// MoveObject moves an object by copying it and deleting the source once the
+// copy succeeded. Large objects are moved by copying their manifest, so
+// their segments stay where they are.
+func (c *Client) MoveObject(fromContainerName string, fromObjectName string, toContainerName string, toObjectName string) (http.Header, error) {
+	if fromContainerName == toContainerName && fromObjectName == toObjectName {
+		return nil, errors.New("Source and destination are the same object.")
+	}
+	source, err := c.ShowObjectMeta(fromContainerName, fromObjectName)
+	if err != nil {
+		return nil, err
+	}
+	opts := CopyOptions{ManifestCopy: isManifest(source)}
+	header, err := c.CopyObjectWithOptions(fromContainerName, fromObjectName, toContainerName, toObjectName, opts)
+	if err != nil {
+		return nil, err
+	}
+	return header, c.DeleteObject(fromContainerName, fromObjectName)
+}
+
+// MoveOptions controls MovePrefix.
+type MoveOptions struct {
+	// Concurrency is the number of objects moved at once. Defaults to 8.
+	Concurrency int
+}
+
+// MoveError is an object that could not be moved.
+type MoveError struct {
+	Name string
+	Err  error
+}
+
+// MoveReport lists the source objects that were moved and those that were
+// not. Objects that failed are left in place.
+type MoveReport struct {
+	Moved  []string
+	Failed []MoveError
+}
+
+type moveTask struct {
+	name   string
+	header http.Header
+	err    error
+	// segments referenced by the source manifest
+	segments []string
+}
+
+// MovePrefix moves every object of fromContainerName starting with
+// fromPrefix to toContainerName, replacing fromPrefix with toPrefix. Within
+// one container, neither prefix may start with the other.
+//
+// Plain objects are copied first, then large object manifests are
+// re-pointed at the new location of segments that were moved along with
+// them, and only then are sources deleted. Segments still referenced by a
+// manifest that failed to move are not deleted.
+func (c *Client) MovePrefix(fromContainerName string, fromPrefix string, toContainerName string, toPrefix string, opts MoveOptions) (*MoveReport, error) {
+	if fromContainerName == toContainerName && (strings.HasPrefix(toPrefix, fromPrefix) || strings.HasPrefix(fromPrefix, toPrefix)) {
+		// Copies would overwrite sources that are yet to be moved and then
+		// be deleted as sources themselves.
+		return nil, errors.New("Source and destination prefixes overlap.")
+	}
+	if err := c.prepare(); err != nil {
+		return nil, err
+	}
+	objects, err := c.ListAllObjects(fromContainerName, Params{Prefix: fromPrefix})
+	if err != nil {
+		return nil, err
+	}
+	rename := func(container string, name string) (string, string, bool) {
+		if container == fromContainerName && strings.HasPrefix(name, fromPrefix) {
+			return toContainerName, toPrefix + strings.TrimPrefix(name, fromPrefix), true
+		}
+		return container, name, false
+	}
+	tasks := make([]*moveTask, len(objects))
+	for i := range objects {
+		tasks[i] = &moveTask{name: objects[i].Name}
+	}
+
+	// Copy plain objects and remember the manifests.
+	var mu sync.Mutex
+	var manifests []*moveTask
+	parallel(opts.Concurrency, len(tasks), func(i int) {
+		t := tasks[i]
+		t.header, t.err = c.ShowObjectMeta(fromContainerName, t.name)
+		if t.err != nil {
+			return
+		}
+		if isManifest(t.header) {
+			mu.Lock()
+			manifests = append(manifests, t)
+			mu.Unlock()
+			return
+		}
+		_, newName, _ := rename(fromContainerName, t.name)
+		_, t.err = c.CopyObject(fromContainerName, t.name, toContainerName, newName)
+	})
+
+	// Every plain object, and whether it was copied.
+	copied := make(map[string]bool)
+	for _, t := range tasks {
+		if t.header == nil || !isManifest(t.header) {
+			copied[t.name] = t.err == nil
+		}
+	}
+
+	// Re-point manifests at the moved segments.
+	parallel(opts.Concurrency, len(manifests), func(i int) {
+		t := manifests[i]
+		_, newName, _ := rename(fromContainerName, t.name)
+		t.segments, t.err = c.moveManifest(fromContainerName, t.name, toContainerName, newName, t.header, rename, copied)
+	})
+
+	// Delete the sources, keeping segments of manifests that stayed behind.
+	keep := make(map[string]bool)
+	for _, t := range manifests {
+		if t.err != nil {
+			for _, s := range t.segments {
+				keep[s] = true
+			}
+		}
+	}
+	parallel(opts.Concurrency, len(tasks), func(i int) {
+		t := tasks[i]
+		if t.err == nil && !keep[t.name] {
+			if err := c.DeleteObject(fromContainerName, t.name); err != nil && !isNotFound(err) {
+				t.err = fmt.Errorf("copied but not deleted: %s", err)
+			}
+		}
+	})
+
+	report := new(MoveReport)
+	for _, t := range tasks {
+		switch {
+		case t.err != nil:
+			report.Failed = append(report.Failed, MoveError{Name: t.name, Err: t.err})
+		case keep[t.name]:
+			report.Failed = append(report.Failed, MoveError{Name: t.name, Err: errors.New("copied but kept for a manifest that was not moved")})
+		default:
+			report.Moved = append(report.Moved, t.name)
+		}
+	}
+	sort.Strings(report.Moved)
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].Name < report.Failed[j].Name })
+	if len(report.Failed) != 0 {
+		return report, fmt.Errorf("Could not move %d of %d objects.", len(report.Failed), len(tasks))
+	}
+	return report, nil
+}
+
+// moveManifest writes the manifest of a moved large object, pointing it at
+// the new location of segments that were copied along with it. It returns
+// the names of segments in fromContainerName the source manifest refers to.
+// copied holds the plain objects being moved and whether their copy
+// succeeded.
+func (c *Client) moveManifest(fromContainerName string, fromObjectName string, toContainerName string, toObjectName string, header http.Header,
+	rename func(string, string) (string, string, bool), copied map[string]bool) ([]string, error) {
+	var segments []string
+	if prefix := header.Get("X-Object-Manifest"); prefix != "" {
+		parts := strings.SplitN(prefix, "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid X-Object-Manifest: %s", prefix)
+		}
+		container, segPrefix, moved := rename(parts[0], parts[1])
+		if !moved {
+			_, err := c.CopyObjectWithOptions(fromContainerName, fromObjectName, toContainerName, toObjectName, CopyOptions{ManifestCopy: true})
+			return nil, err
+		}
+		var missing []string
+		for name, ok := range copied {
+			if strings.HasPrefix(name, parts[1]) {
+				segments = append(segments, name)
+				if !ok {
+					missing = append(missing, name)
+				}
+			}
+		}
+		if len(missing) != 0 {
+			sort.Strings(missing)
+			return segments, fmt.Errorf("Segment %s/%s was not moved.", parts[0], missing[0])
+		}
+		metadata := preservedMetadata(header)
+		_, err := c.CreateDLOManifest(toContainerName, toObjectName, container, segPrefix, metadata)
+		return segments, err
+	}
+
+	entries, _, err := c.GetSLOManifest(fromContainerName, fromObjectName)
+	if err != nil {
+		return nil, err
+	}
+	newSegments := make([]SLOSegment, len(entries))
+	repointed := false
+	for i, e := range entries {
+		newSegments[i] = e.Segment()
+		parts := strings.SplitN(strings.TrimPrefix(e.Name, "/"), "/", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if parts[0] == fromContainerName {
+			segments = append(segments, parts[1])
+		}
+		if container, name, moved := rename(parts[0], parts[1]); moved {
+			if !copied[parts[1]] {
+				return segments, fmt.Errorf("Segment %s was not moved.", e.Name)
+			}
+			newSegments[i].Path = fmt.Sprintf("/%s/%s", container, name)
+			repointed = true
+		}
+	}
+	if !repointed {
+		_, err := c.CopyObjectWithOptions(fromContainerName, fromObjectName, toContainerName, toObjectName, CopyOptions{ManifestCopy: true})
+		return segments, err
+	}
+	_, err = c.CreateSLOManifest(toContainerName, toObjectName, newSegments, preservedMetadata(header))
+	return segments, err
+}
@@ -0,0 +1,108 @@
+package goswift
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	var sum int64
+	seen := make([]int32, 100)
+	parallel(4, len(seen), func(i int) {
+		atomic.AddInt32(&seen[i], 1)
+		atomic.AddInt64(&sum, int64(i))
+	})
+	for i := range seen {
+		if seen[i] != 1 {
+			t.Errorf("Expected error: index %d was called %d times", i, seen[i])
+		}
+	}
+	if sum != 4950 {
+		t.Errorf("Expected error: unexpected sum %d", sum)
+	}
+}
+
+func TestPreservedMetadata(t *testing.T) {
+	h := make(http.Header)
+	h.Set("Content-Type", "video/mp4")
+	h.Set("Content-Length", "1024")
+	h.Set("Etag", "d41d8cd98f00b204e9800998ecf8427e")
+	h.Set("X-Object-Meta-Author", "saka01")
+	h.Set("X-Static-Large-Object", "True")
+	h.Set("X-Delete-At", "1500000000")
+	metadata := http.Header(preservedMetadata(h))
+	if len(metadata) != 3 || metadata.Get("Content-Type") != "video/mp4" || metadata.Get("X-Object-Meta-Author") != "saka01" {
+		t.Errorf("Expected error: unexpected metadata %v", metadata)
+	}
+	if !isManifest(h) {
+		t.Errorf("Expected error: %s", "SLO manifest was not detected.")
+	}
+}
+
+func TestMovePrefixDLOMissingSegment(t *testing.T) {
+	s, c := newFakeSwift(t)
+	for _, name := range []string{"old/big/seg/1", "old/big/seg/2", "old/big/seg/3"} {
+		s.putObject("c", name, []byte(name), nil)
+	}
+	s.putObject("c", "old/big", nil, http.Header{"X-Object-Manifest": {"c/old/big/seg/"}})
+	s.fail = func(r *http.Request) int {
+		if r.Method == "COPY" && r.URL.Path == "/v1/AUTH_test/c/old/big/seg/2" {
+			return http.StatusServiceUnavailable
+		}
+		return 0
+	}
+	report, err := c.MovePrefix("c", "old/", "c", "new/", MoveOptions{})
+	if err == nil {
+		t.Fatalf("Expected error: %s", "Move with a missing segment succeeded.")
+	}
+	for _, name := range report.Moved {
+		if name == "old/big" {
+			t.Errorf("Expected error: %s", "Manifest was reported as moved.")
+		}
+	}
+	if s.object("c", "new/big") != nil {
+		t.Errorf("Expected error: %s", "Truncated manifest was written.")
+	}
+	for _, name := range []string{"old/big", "old/big/seg/1", "old/big/seg/2", "old/big/seg/3"} {
+		if s.object("c", name) == nil {
+			t.Errorf("Expected error: %s was deleted", name)
+		}
+	}
+
+	s.fail = nil
+	if _, err := c.MovePrefix("c", "old/", "c", "new/", MoveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	o := s.object("c", "new/big")
+	if o == nil || o.header.Get("X-Object-Manifest") != "c/new/big/seg/" {
+		t.Errorf("Expected error: %s", "Manifest was not re-pointed.")
+	}
+	if names := s.objectNames("c"); len(names) != 4 || names[0] != "new/big" {
+		t.Errorf("Expected error: unexpected objects %v", names)
+	}
+}
+
+func TestMovePrefixOverlap(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "photos/x", []byte("outer"), nil)
+	s.putObject("c", "photos/2014/x", []byte("inner"), nil)
+	for _, p := range [][2]string{{"photos/", "photos/2014/"}, {"photos/2014/", "photos/"}, {"photos/", "photos/"}, {"", "backup/"}} {
+		if _, err := c.MovePrefix("c", p[0], "c", p[1], MoveOptions{}); err == nil {
+			t.Errorf("Expected error: moving %q to %q was accepted", p[0], p[1])
+		}
+	}
+	if names := s.objectNames("c"); len(names) != 2 || string(s.object("c", "photos/x").data) != "outer" || string(s.object("c", "photos/2014/x").data) != "inner" {
+		t.Errorf("Expected error: objects were changed: %v", names)
+	}
+	// the same prefixes in different containers do not overlap
+	if _, err := c.CreateContainer("d"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.MovePrefix("c", "photos/", "d", "photos/2014/", MoveOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if o := s.object("d", "photos/2014/x"); o == nil || string(o.data) != "outer" {
+		t.Errorf("Expected error: unexpected objects %v", s.objectNames("d"))
+	}
+}
@@ -0,0 +1,88 @@
+package goswift
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ContainerOptions are sent when a container is created.
+type ContainerOptions struct {
+	// StoragePolicy places the container in a storage policy other than the
+	// cluster's default. It cannot be changed once the container exists.
+	StoragePolicy string
+	Metadata      Metadata
+}
+
+func (c *Client) CreateContainerWithOptions(containerName string, opts ContainerOptions) (http.Header, error) {
+	header := make(http.Header)
+	for k, v := range opts.Metadata {
+		header[k] = v
+	}
+	if opts.StoragePolicy != "" {
+		header.Set("X-Storage-Policy", opts.StoragePolicy)
+	}
+	_, h, err := c.request("PUT", containerName, nil, 0, header, nil)
+	return h, err
+}
+
+// PolicyUsage is the account usage of one storage policy.
+type PolicyUsage struct {
+	BytesUsed      int64
+	ObjectCount    int64
+	ContainerCount int64
+}
+
+const policyHeaderPrefix = "X-Account-Storage-Policy-"
+
+// parsePolicyUsage reads the X-Account-Storage-Policy-<name>-* headers of an
+// account HEAD.
+func parsePolicyUsage(h http.Header) (map[string]PolicyUsage, error) {
+	usage := make(map[string]PolicyUsage)
+	for k := range h {
+		k = http.CanonicalHeaderKey(k)
+		if !strings.HasPrefix(k, policyHeaderPrefix) {
+			continue
+		}
+		rest := k[len(policyHeaderPrefix):]
+		for _, suffix := range []string{"-Bytes-Used", "-Object-Count", "-Container-Count"} {
+			if !strings.HasSuffix(rest, suffix) || len(rest) == len(suffix) {
+				continue
+			}
+			name := strings.ToLower(strings.TrimSuffix(rest, suffix))
+			n, err := headerInt64(h, k)
+			if err != nil {
+				return nil, err
+			}
+			u := usage[name]
+			switch suffix {
+			case "-Bytes-Used":
+				u.BytesUsed = n
+			case "-Object-Count":
+				u.ObjectCount = n
+			default:
+				u.ContainerCount = n
+			}
+			usage[name] = u
+		}
+	}
+	return usage, nil
+}
+
+// AccountPolicyUsage returns the account usage per storage policy.
+func (c *Client) AccountPolicyUsage() (map[string]PolicyUsage, error) {
+	info, err := c.StatAccount()
+	if err != nil {
+		return nil, err
+	}
+	return info.Policies, nil
+}
+
+// StoragePolicies returns the storage policies the cluster publishes on
+// /info.
+func (c *Client) StoragePolicies() ([]PolicyInfo, error) {
+	caps, err := c.GetCapabilities()
+	if err != nil {
+		return nil, err
+	}
+	return caps.Swift.Policies, nil
+}
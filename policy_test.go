@@ -0,0 +1,28 @@
+package goswift
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParsePolicyUsage(t *testing.T) {
+	h := make(http.Header)
+	h.Set("X-Account-Bytes-Used", "300")
+	h.Set("X-Account-Storage-Policy-Gold-Bytes-Used", "100")
+	h.Set("X-Account-Storage-Policy-Gold-Object-Count", "2")
+	h.Set("X-Account-Storage-Policy-Gold-Container-Count", "1")
+	h.Set("X-Account-Storage-Policy-Ec-Cold-Bytes-Used", "200")
+	info, err := ParseAccountInfo(h)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if len(info.Policies) != 2 {
+		t.Fatalf("Expected error: unexpected policies %v", info.Policies)
+	}
+	if u := info.Policies["gold"]; u.BytesUsed != 100 || u.ObjectCount != 2 || u.ContainerCount != 1 {
+		t.Errorf("Expected error: unexpected gold usage %+v", u)
+	}
+	if u := info.Policies["ec-cold"]; u.BytesUsed != 200 {
+		t.Errorf("Expected error: unexpected ec-cold usage %+v", u)
+	}
+}
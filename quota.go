@@ -0,0 +1,91 @@
+package goswift
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// QuotaExceededError is returned when a write is rejected by the container
+// or account quota middleware.
+type QuotaExceededError struct {
+	Err *Error
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("Swift API: quota exceeded: %v", e.Err.Body)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return e.Err
+}
+
+// IsQuotaExceeded reports whether err is a *QuotaExceededError.
+func IsQuotaExceeded(err error) bool {
+	var q *QuotaExceededError
+	return errors.As(err, &q)
+}
+
+// ContainerQuota is the quota of a container. Zero means no limit.
+type ContainerQuota struct {
+	Bytes int64
+	Count int64
+}
+
+func (c *Client) GetContainerQuota(containerName string) (ContainerQuota, error) {
+	header, err := c.ShowContainerMeta(containerName)
+	if err != nil {
+		return ContainerQuota{}, err
+	}
+	var q ContainerQuota
+	if q.Bytes, err = headerInt64(header, "X-Container-Meta-Quota-Bytes"); err != nil {
+		return ContainerQuota{}, err
+	}
+	if q.Count, err = headerInt64(header, "X-Container-Meta-Quota-Count"); err != nil {
+		return ContainerQuota{}, err
+	}
+	return q, nil
+}
+
+// SetContainerQuota replaces the quota of the container. Zero fields remove
+// the corresponding limit.
+func (c *Client) SetContainerQuota(containerName string, q ContainerQuota) (http.Header, error) {
+	metadata := NewMetadata()
+	setQuotaHeader(&metadata, "X-Container-Meta-Quota-Bytes", q.Bytes)
+	setQuotaHeader(&metadata, "X-Container-Meta-Quota-Count", q.Count)
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+func (c *Client) ClearContainerQuota(containerName string) (http.Header, error) {
+	return c.SetContainerQuota(containerName, ContainerQuota{})
+}
+
+// GetAccountQuota returns the byte quota of the account, or zero if there is
+// none.
+func (c *Client) GetAccountQuota() (int64, error) {
+	header, err := c.ShowAccountMeta()
+	if err != nil {
+		return 0, err
+	}
+	return headerInt64(header, "X-Account-Meta-Quota-Bytes")
+}
+
+// SetAccountQuota sets the byte quota of the account. Only reseller admins
+// may do this. Zero removes the quota.
+func (c *Client) SetAccountQuota(bytes int64) (http.Header, error) {
+	metadata := NewMetadata()
+	setQuotaHeader(&metadata, "X-Account-Meta-Quota-Bytes", bytes)
+	return c.CreateAccountMeta(metadata)
+}
+
+func (c *Client) ClearAccountQuota() (http.Header, error) {
+	return c.SetAccountQuota(0)
+}
+
+func setQuotaHeader(m *Metadata, key string, value int64) {
+	if value > 0 {
+		m.SetMeta(key, fmt.Sprintf("%d", value))
+	} else {
+		m.SetDeleteMeta(key)
+	}
+}
@@ -0,0 +1,34 @@
+package goswift
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCheckResponseWithQuotaExceeded(t *testing.T) {
+	res := &http.Response{StatusCode: 413, Body: ioutil.NopCloser(strings.NewReader("Upload exceeds quota."))}
+	err := CheckResponse(res)
+	if !IsQuotaExceeded(err) {
+		t.Fatalf("Expected error: unexpected error %#v", err)
+	}
+	if err.(*QuotaExceededError).Err.Code != 413 {
+		t.Errorf("Expected error: unexpected code %d", err.(*QuotaExceededError).Err.Code)
+	}
+
+	res = &http.Response{StatusCode: 413, Body: ioutil.NopCloser(strings.NewReader("Your request is too large."))}
+	if err := CheckResponse(res); IsQuotaExceeded(err) {
+		t.Errorf("Expected error: %s", "Oversized request was reported as quota exceeded.")
+	}
+}
+
+func TestSetQuotaHeader(t *testing.T) {
+	metadata := NewMetadata()
+	setQuotaHeader(&metadata, "X-Container-Meta-Quota-Bytes", 1024)
+	setQuotaHeader(&metadata, "X-Container-Meta-Quota-Count", 0)
+	h := http.Header(metadata)
+	if h.Get("X-Container-Meta-Quota-Bytes") != "1024" || h.Get("X-Remove-Container-Meta-Quota-Count") != "x" {
+		t.Errorf("Expected error: unexpected headers %v", h)
+	}
+}
@@ -0,0 +1,263 @@
+package goswift
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+const (
+	defaultBlockSize   = 1 << 20
+	defaultCacheBlocks = 16
+)
+
+// ReaderOptions controls OpenObject.
+type ReaderOptions struct {
+	// BlockSize is the size of the ranged GETs the object is read with.
+	// Defaults to 1 MiB.
+	BlockSize int64
+	// CacheBlocks is the number of blocks kept in memory, least recently
+	// used first out. Defaults to 16.
+	CacheBlocks int
+	// ReadAhead is the number of blocks Read fetches in the background
+	// after the block it reads from. Zero disables read-ahead. It is
+	// limited to CacheBlocks-1.
+	ReadAhead int
+}
+
+// ObjectReader gives random access to an object with ranged GETs. It
+// implements io.ReadSeeker and io.ReaderAt, so for example a zip archive can
+// be opened with zip.NewReader(r, r.Size()) and a single member read without
+// downloading the whole archive.
+//
+// ReadAt may be called concurrently; Read and Seek share an offset and may
+// not.
+type ObjectReader struct {
+	size   int64
+	header http.Header
+	opts   ReaderOptions
+	fetch  func(offset int64, length int64) ([]byte, error)
+
+	mu      sync.Mutex
+	lru     *list.List
+	cache   map[int64]*list.Element
+	pending map[int64]*blockFetch
+	offset  int64
+	closed  bool
+}
+
+type cachedBlock struct {
+	index int64
+	data  []byte
+}
+
+type blockFetch struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// OpenObject returns a reader over an object. It HEADs the object for its
+// size; the content is only fetched as it is read. If the object is
+// replaced meanwhile, reads that need to fetch a block fail rather than mix
+// the two versions.
+func (c *Client) OpenObject(containerName string, objectName string, opts ReaderOptions) (*ObjectReader, error) {
+	header, err := c.ShowObjectMeta(containerName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	info, err := ParseObjectInfo(header)
+	if err != nil {
+		return nil, err
+	}
+	// Blocks are only read from the object as it was when it was opened.
+	etag := header.Get("Etag")
+	r := newObjectReader(info.ContentLength, opts, func(offset int64, length int64) ([]byte, error) {
+		body, err := c.getObjectRange(containerName, objectName, offset, length, etag)
+		if e, ok := err.(*Error); ok && e.Code == http.StatusPreconditionFailed {
+			return nil, fmt.Errorf("%s/%s changed since it was opened: %s", containerName, objectName, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		data := make([]byte, length)
+		if _, err := io.ReadFull(body, data); err != nil {
+			return nil, fmt.Errorf("Could not read %d bytes at %d of %s/%s: %s", length, offset, containerName, objectName, err)
+		}
+		return data, nil
+	})
+	r.header = header
+	return r, nil
+}
+
+func newObjectReader(size int64, opts ReaderOptions, fetch func(int64, int64) ([]byte, error)) *ObjectReader {
+	if opts.BlockSize <= 0 {
+		opts.BlockSize = defaultBlockSize
+	}
+	if opts.CacheBlocks <= 0 {
+		opts.CacheBlocks = defaultCacheBlocks
+	}
+	if opts.ReadAhead >= opts.CacheBlocks {
+		opts.ReadAhead = opts.CacheBlocks - 1
+	}
+	return &ObjectReader{
+		size:    size,
+		opts:    opts,
+		fetch:   fetch,
+		lru:     list.New(),
+		cache:   make(map[int64]*list.Element),
+		pending: make(map[int64]*blockFetch),
+	}
+}
+
+// Size returns the size of the object.
+func (r *ObjectReader) Size() int64 {
+	return r.size
+}
+
+// Header returns the headers of the object HEAD.
+func (r *ObjectReader) Header() http.Header {
+	return r.header
+}
+
+func (r *ObjectReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("Negative offset.")
+	}
+	n := 0
+	for n < len(p) {
+		if off >= r.size {
+			return n, io.EOF
+		}
+		index := off / r.opts.BlockSize
+		data, err := r.block(index)
+		if err != nil {
+			return n, err
+		}
+		c := copy(p[n:], data[off-index*r.opts.BlockSize:])
+		n += c
+		off += int64(c)
+	}
+	return n, nil
+}
+
+func (r *ObjectReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	off := r.offset
+	r.mu.Unlock()
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, err := r.ReadAt(p, off)
+	if n > 0 && err == io.EOF {
+		err = nil
+	}
+	r.mu.Lock()
+	r.offset = off + int64(n)
+	r.mu.Unlock()
+	if n > 0 {
+		r.readAhead((off + int64(n) - 1) / r.opts.BlockSize)
+	}
+	return n, err
+}
+
+func (r *ObjectReader) Seek(offset int64, whence int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("Invalid whence.")
+	}
+	if offset < 0 {
+		return 0, errors.New("Negative offset.")
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+// Close drops the cached blocks. Fetches in flight are not cancelled.
+func (r *ObjectReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	r.lru.Init()
+	r.cache = make(map[int64]*list.Element)
+	return nil
+}
+
+// block returns a block from the cache, waits for a fetch in flight or
+// fetches it.
+func (r *ObjectReader) block(index int64) ([]byte, error) {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil, errors.New("Reader is closed.")
+	}
+	if e, ok := r.cache[index]; ok {
+		r.lru.MoveToFront(e)
+		r.mu.Unlock()
+		return e.Value.(*cachedBlock).data, nil
+	}
+	f, ok := r.pending[index]
+	if !ok {
+		f = r.startFetch(index)
+	}
+	r.mu.Unlock()
+	<-f.done
+	return f.data, f.err
+}
+
+// startFetch fetches a block in the background. r.mu must be held.
+func (r *ObjectReader) startFetch(index int64) *blockFetch {
+	f := &blockFetch{done: make(chan struct{})}
+	r.pending[index] = f
+	go func() {
+		offset := index * r.opts.BlockSize
+		length := r.opts.BlockSize
+		if r.size-offset < length {
+			length = r.size - offset
+		}
+		f.data, f.err = r.fetch(offset, length)
+		r.mu.Lock()
+		delete(r.pending, index)
+		if f.err == nil && !r.closed {
+			r.cache[index] = r.lru.PushFront(&cachedBlock{index: index, data: f.data})
+			for r.lru.Len() > r.opts.CacheBlocks {
+				e := r.lru.Back()
+				r.lru.Remove(e)
+				delete(r.cache, e.Value.(*cachedBlock).index)
+			}
+		}
+		r.mu.Unlock()
+		close(f.done)
+	}()
+	return f
+}
+
+// readAhead starts fetching the blocks after index that are neither cached
+// nor being fetched.
+func (r *ObjectReader) readAhead(index int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	for i := index + 1; i <= index+int64(r.opts.ReadAhead) && i*r.opts.BlockSize < r.size; i++ {
+		if _, ok := r.cache[i]; ok {
+			continue
+		}
+		if _, ok := r.pending[i]; ok {
+			continue
+		}
+		r.startFetch(i)
+	}
+}
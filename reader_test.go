@@ -0,0 +1,123 @@
+package goswift
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+type fakeObject struct {
+	mu      sync.Mutex
+	content []byte
+	fetches int
+}
+
+func (o *fakeObject) fetch(offset int64, length int64) ([]byte, error) {
+	o.mu.Lock()
+	o.fetches++
+	o.mu.Unlock()
+	return append([]byte(nil), o.content[offset:offset+length]...), nil
+}
+
+func (o *fakeObject) count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.fetches
+}
+
+func TestObjectReaderReadAt(t *testing.T) {
+	o := &fakeObject{content: make([]byte, 1000)}
+	for i := range o.content {
+		o.content[i] = byte(i)
+	}
+	r := newObjectReader(int64(len(o.content)), ReaderOptions{BlockSize: 64, CacheBlocks: 4}, o.fetch)
+	p := make([]byte, 100)
+	n, err := r.ReadAt(p, 50)
+	if n != 100 || err != nil || !bytes.Equal(p, o.content[50:150]) {
+		t.Errorf("Expected error: read %d bytes, %v", n, err)
+	}
+	if o.count() != 3 {
+		t.Errorf("Expected error: %d fetches, expected 3", o.count())
+	}
+	r.ReadAt(p, 60)
+	if o.count() != 3 {
+		t.Errorf("Expected error: cached blocks were fetched again")
+	}
+	n, err = r.ReadAt(p, 950)
+	if n != 50 || err != io.EOF || !bytes.Equal(p[:n], o.content[950:]) {
+		t.Errorf("Expected error: read %d bytes at the end, %v", n, err)
+	}
+	// Blocks 0 and 1 were evicted for blocks 14 and 15.
+	r.ReadAt(p[:1], 0)
+	if o.count() != 6 {
+		t.Errorf("Expected error: %d fetches, expected 6", o.count())
+	}
+}
+
+func TestObjectReaderReadSeek(t *testing.T) {
+	o := &fakeObject{content: bytes.Repeat([]byte("0123456789"), 100)}
+	r := newObjectReader(int64(len(o.content)), ReaderOptions{BlockSize: 128, CacheBlocks: 4, ReadAhead: 2}, o.fetch)
+	b, err := ioutil.ReadAll(r)
+	if err != nil || !bytes.Equal(b, o.content) {
+		t.Errorf("Expected error: read %d bytes, %v", len(b), err)
+	}
+	if pos, err := r.Seek(-5, io.SeekEnd); pos != 995 || err != nil {
+		t.Errorf("Expected error: seek to %d, %v", pos, err)
+	}
+	b, _ = ioutil.ReadAll(r)
+	if string(b) != "56789" {
+		t.Errorf("Expected error: read %q after seek", b)
+	}
+	if _, err := r.Seek(-1, io.SeekStart); err == nil {
+		t.Errorf("Expected error: negative offset was accepted")
+	}
+}
+
+func TestObjectReaderZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		w, _ := zw.Create(name)
+		w.Write(bytes.Repeat([]byte(name), 1000))
+	}
+	zw.Close()
+	o := &fakeObject{content: buf.Bytes()}
+	r := newObjectReader(int64(buf.Len()), ReaderOptions{BlockSize: 256}, o.fetch)
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	f, err := zr.File[1].Open()
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	b, err := ioutil.ReadAll(f)
+	if err != nil || !bytes.Equal(b, bytes.Repeat([]byte("b.txt"), 1000)) {
+		t.Errorf("Expected error: unexpected member content, %v", err)
+	}
+}
+
+func TestOpenObjectChanged(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "data", []byte("0123456789"), nil)
+	r, err := c.OpenObject("c", "data", ReaderOptions{BlockSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	p := make([]byte, 4)
+	if _, err := r.ReadAt(p, 0); err != nil || string(p) != "0123" {
+		t.Fatalf("Expected error: read %q, %v", p, err)
+	}
+	gets := s.recorded("GET")
+	if len(gets) != 1 || gets[0].Header.Get("If-Match") != md5Hex([]byte("0123456789")) || gets[0].Header.Get("Range") != "bytes=0-3" {
+		t.Errorf("Expected error: unexpected GET %+v", gets)
+	}
+	s.putObject("c", "data", []byte("abcdefghij"), nil)
+	if _, err := r.ReadAt(p, 4); err == nil {
+		t.Errorf("Expected error: read %q from a replaced object", p)
+	}
+}
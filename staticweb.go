@@ -0,0 +1,184 @@
+package goswift
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// StaticWebConfig is the staticweb configuration of a container.
+type StaticWebConfig struct {
+	// Index is served for requests to pseudo-directories, e.g. "index.html".
+	Index string
+	// Error is the suffix of error pages, e.g. "error.html" serves
+	// "404error.html" for missing objects.
+	Error string
+	// Listings enables listings of pseudo-directories without an index.
+	Listings bool
+	// ListingsCSS is the stylesheet object used for listings.
+	ListingsCSS string
+	// DirectoryType is the content type of directory marker objects.
+	DirectoryType string
+}
+
+func (c *Client) GetStaticWeb(containerName string) (StaticWebConfig, error) {
+	header, err := c.ShowContainerMeta(containerName)
+	if err != nil {
+		return StaticWebConfig{}, err
+	}
+	h := http.Header(header)
+	listings, _ := strconv.ParseBool(h.Get("X-Container-Meta-Web-Listings"))
+	return StaticWebConfig{
+		Index:         h.Get("X-Container-Meta-Web-Index"),
+		Error:         h.Get("X-Container-Meta-Web-Error"),
+		Listings:      listings,
+		ListingsCSS:   h.Get("X-Container-Meta-Web-Listings-Css"),
+		DirectoryType: h.Get("X-Container-Meta-Web-Directory-Type"),
+	}, nil
+}
+
+// SetStaticWeb replaces the staticweb configuration of the container. Empty
+// fields are removed.
+func (c *Client) SetStaticWeb(containerName string, cfg StaticWebConfig) (http.Header, error) {
+	metadata := NewMetadata()
+	setOrDeleteMeta(&metadata, "X-Container-Meta-Web-Index", cfg.Index)
+	setOrDeleteMeta(&metadata, "X-Container-Meta-Web-Error", cfg.Error)
+	if cfg.Listings {
+		metadata.SetMeta("X-Container-Meta-Web-Listings", "true")
+	} else {
+		metadata.SetDeleteMeta("X-Container-Meta-Web-Listings")
+	}
+	setOrDeleteMeta(&metadata, "X-Container-Meta-Web-Listings-Css", cfg.ListingsCSS)
+	setOrDeleteMeta(&metadata, "X-Container-Meta-Web-Directory-Type", cfg.DirectoryType)
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+func setOrDeleteMeta(m *Metadata, key string, value string) {
+	if value != "" {
+		m.SetMeta(key, value)
+	} else {
+		m.SetDeleteMeta(key)
+	}
+}
+
+// ObjectError is an object an operation failed for.
+type ObjectError struct {
+	Name string
+	Err  error
+}
+
+// PublishReport lists the objects uploaded by PublishSite.
+type PublishReport struct {
+	Uploaded []string
+	Failed   []ObjectError
+}
+
+// PublishSite uploads the files below dir to the container, creating it if
+// needed, with content types guessed from their extensions. It then applies
+// cfg and makes the container publicly readable, with listings if
+// cfg.Listings is set. If cfg.DirectoryType is set, a directory marker of
+// that type is created for every subdirectory.
+func (c *Client) PublishSite(containerName string, dir string, cfg StaticWebConfig) (*PublishReport, error) {
+	if err := c.prepare(); err != nil {
+		return nil, err
+	}
+	c.capabilities()
+	var files, dirs []string
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		if fi.IsDir() {
+			dirs = append(dirs, filepath.ToSlash(rel))
+		} else if fi.Mode().IsRegular() {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.CreateContainer(containerName); err != nil {
+		return nil, err
+	}
+
+	report := new(PublishReport)
+	var mu sync.Mutex
+	parallel(defaultConcurrency, len(files), func(i int) {
+		name := files[i]
+		contentType, err := detectContentType(filepath.Join(dir, filepath.FromSlash(name)))
+		if err == nil {
+			metadata := NewMetadata()
+			metadata.SetMeta("Content-Type", contentType)
+			_, err = c.CreateObject(containerName, name, filepath.Join(dir, filepath.FromSlash(name)), metadata)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			report.Failed = append(report.Failed, ObjectError{Name: name, Err: err})
+		} else {
+			report.Uploaded = append(report.Uploaded, name)
+		}
+	})
+	if cfg.DirectoryType != "" {
+		for _, name := range dirs {
+			if _, err := c.CreateDirectoryMarker(containerName, name, cfg.DirectoryType); err != nil {
+				report.Failed = append(report.Failed, ObjectError{Name: name, Err: err})
+			}
+		}
+	}
+	sort.Strings(report.Uploaded)
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].Name < report.Failed[j].Name })
+	if len(report.Failed) != 0 {
+		return report, fmt.Errorf("Could not upload %d of %d files.", len(report.Failed), len(files))
+	}
+
+	if _, err := c.SetStaticWeb(containerName, cfg); err != nil {
+		return report, err
+	}
+	acl := ACL{ReferrerGrant("*")}
+	if cfg.Listings {
+		acl = append(acl, ListingsGrant())
+	}
+	_, err = c.GrantContainerRead(containerName, acl...)
+	return report, err
+}
+
+// CreateDirectoryMarker creates the zero byte object other Swift tools use
+// to mark a pseudo-directory. contentType is usually "application/directory".
+func (c *Client) CreateDirectoryMarker(containerName string, dirName string, contentType string) (http.Header, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, dirName)
+	metadata := NewMetadata()
+	metadata.SetMeta("Content-Type", contentType)
+	_, header, err := c.request("PUT", objectPath, nil, 0, http.Header(metadata), nil)
+	return header, err
+}
+
+// detectContentType guesses the content type of a file from its extension,
+// falling back to sniffing its first bytes.
+func detectContentType(name string) (string, error) {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
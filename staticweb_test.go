@@ -0,0 +1,82 @@
+package goswift
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectContentType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goswift")
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "README"), []byte("<!DOCTYPE html><html></html>"), 0644)
+	if ct, err := detectContentType(filepath.Join(dir, "index.html")); err != nil || !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Expected error: unexpected content type %s %v", ct, err)
+	}
+	if ct, err := detectContentType(filepath.Join(dir, "README")); err != nil || !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("Expected error: unexpected content type %s %v", ct, err)
+	}
+	if _, err := detectContentType(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Expected error: %s", "Missing file was accepted.")
+	}
+}
+
+func TestPublishSite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goswift")
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	os.MkdirAll(filepath.Join(dir, "css"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "index.html"), []byte("<html></html>"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "css", "site.css"), []byte("body {}"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "README"), []byte("<!DOCTYPE html><html></html>"), 0644)
+
+	for _, cfg := range []StaticWebConfig{
+		{Index: "index.html", Error: "error.html", Listings: true, DirectoryType: DirectoryType},
+		{Index: "index.html"},
+	} {
+		s, c := newFakeSwift(t)
+		report, err := c.PublishSite("site", dir, cfg)
+		if err != nil {
+			t.Fatalf("Expected error: %s", err)
+		}
+		if strings.Join(report.Uploaded, ",") != "README,css/site.css,index.html" || len(report.Failed) != 0 {
+			t.Errorf("Expected error: unexpected report %+v", report)
+		}
+		for name, contentType := range map[string]string{"index.html": "text/html", "README": "text/html", "css/site.css": "text/css"} {
+			if o := s.object("site", name); o == nil || !strings.HasPrefix(o.header.Get("Content-Type"), contentType) {
+				t.Errorf("Expected error: %s was not uploaded as %s", name, contentType)
+			}
+		}
+		marker := s.object("site", "css")
+		if cfg.DirectoryType != "" && (marker == nil || marker.header.Get("Content-Type") != DirectoryType) {
+			t.Errorf("Expected error: %s", "Directory marker was not created.")
+		} else if cfg.DirectoryType == "" && marker != nil {
+			t.Errorf("Expected error: %s", "Directory marker was created without DirectoryType.")
+		}
+
+		header, err := c.ShowContainerMeta("site")
+		if err != nil {
+			t.Fatalf("Expected error: %s", err)
+		}
+		h := http.Header(header)
+		if h.Get("X-Container-Meta-Web-Index") != "index.html" || h.Get("X-Container-Meta-Web-Error") != cfg.Error {
+			t.Errorf("Expected error: unexpected staticweb headers %v", h)
+		}
+		if listings := h.Get("X-Container-Meta-Web-Listings") == "true"; listings != cfg.Listings {
+			t.Errorf("Expected error: unexpected X-Container-Meta-Web-Listings %q", h.Get("X-Container-Meta-Web-Listings"))
+		}
+		acl := ParseACL(h.Get("X-Container-Read"))
+		if !acl.Contains(ReferrerGrant("*")) || acl.Contains(ListingsGrant()) != cfg.Listings {
+			t.Errorf("Expected error: unexpected read ACL %q", h.Get("X-Container-Read"))
+		}
+	}
+}
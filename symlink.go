@@ -0,0 +1,109 @@
+package goswift
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SymlinkTarget is the object a symlink points to. Account is only set for
+// cross-account links and Etag only for static links.
+type SymlinkTarget struct {
+	Container string
+	Object    string
+	Account   string
+	Etag      string
+	Bytes     int64
+}
+
+func (t SymlinkTarget) String() string {
+	path := fmt.Sprintf("%s/%s", t.Container, t.Object)
+	if t.Account != "" {
+		path = fmt.Sprintf("%s/%s", t.Account, path)
+	}
+	return path
+}
+
+// escapePath quotes a "container/object" path for headers Swift unquotes,
+// such as X-Symlink-Target and Destination.
+func escapePath(path string) string {
+	return strings.TrimPrefix((&url.URL{Path: "/" + path}).EscapedPath(), "/")
+}
+
+// ParseSymlinkTarget reads the symlink target from the headers of a HEAD.
+// It understands both the X-Symlink-Target headers returned with
+// ?symlink=get and the Content-Location returned when the link was
+// followed. It returns nil if the object is not a symlink.
+func ParseSymlinkTarget(h http.Header) *SymlinkTarget {
+	if v := h.Get("X-Symlink-Target"); v != "" {
+		if p, err := url.PathUnescape(v); err == nil {
+			v = p
+		}
+		parts := strings.SplitN(v, "/", 2)
+		if len(parts) != 2 {
+			return nil
+		}
+		t := &SymlinkTarget{
+			Container: parts[0],
+			Object:    parts[1],
+			Account:   h.Get("X-Symlink-Target-Account"),
+			Etag:      strings.Trim(h.Get("X-Symlink-Target-Etag"), `"`),
+		}
+		t.Bytes, _ = strconv.ParseInt(h.Get("X-Symlink-Target-Bytes"), 10, 64)
+		return t
+	}
+	if v := h.Get("Content-Location"); v != "" {
+		if p, err := url.PathUnescape(v); err == nil {
+			v = p
+		}
+		// /v1/<account>/<container>/<object>
+		parts := strings.SplitN(strings.TrimPrefix(v, "/"), "/", 4)
+		if len(parts) != 4 {
+			return nil
+		}
+		return &SymlinkTarget{Account: parts[1], Container: parts[2], Object: parts[3]}
+	}
+	return nil
+}
+
+// CreateSymlink creates objectName as a symlink to target. A target with an
+// Etag creates a static link, which only resolves while the target still
+// has that ETag.
+func (c *Client) CreateSymlink(containerName string, objectName string, target SymlinkTarget, metadata Metadata) (http.Header, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	h := make(http.Header)
+	for k, v := range metadata {
+		h[k] = v
+	}
+	h.Set("X-Symlink-Target", escapePath(fmt.Sprintf("%s/%s", target.Container, target.Object)))
+	if target.Account != "" {
+		h.Set("X-Symlink-Target-Account", target.Account)
+	}
+	if target.Etag != "" {
+		h.Set("X-Symlink-Target-Etag", target.Etag)
+	}
+	_, header, err := c.request("PUT", objectPath, nil, 0, h, nil)
+	return header, err
+}
+
+// ShowSymlinkMeta is ShowObjectMeta without following the symlink, so the
+// headers describe the link itself.
+func (c *Client) ShowSymlinkMeta(containerName string, objectName string) (http.Header, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	params := make(url.Values)
+	params.Set("symlink", "get")
+	_, header, err := c.request("HEAD", objectPath, nil, 0, nil, params)
+	return header, err
+}
+
+// GetSymlinkTarget returns the target of a symlink without following it, or
+// nil if the object is not a symlink.
+func (c *Client) GetSymlinkTarget(containerName string, objectName string) (*SymlinkTarget, error) {
+	header, err := c.ShowSymlinkMeta(containerName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSymlinkTarget(header), nil
+}
@@ -0,0 +1,34 @@
+package goswift
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseSymlinkTarget(t *testing.T) {
+	h := make(http.Header)
+	h.Set("X-Symlink-Target", "bb94ba2/releases/v1%201.tar")
+	h.Set("X-Symlink-Target-Etag", "d41d8cd98f00b204e9800998ecf8427e")
+	h.Set("X-Symlink-Target-Bytes", "1024")
+	target := ParseSymlinkTarget(h)
+	if target == nil || target.Container != "bb94ba2" || target.Object != "releases/v1 1.tar" || target.Bytes != 1024 {
+		t.Errorf("Expected error: unexpected target %+v", target)
+	}
+
+	h = make(http.Header)
+	h.Set("Content-Location", "/v1/AUTH_test/bb94ba2/releases/v1.tar")
+	target = ParseSymlinkTarget(h)
+	if target == nil || target.Account != "AUTH_test" || target.String() != "AUTH_test/bb94ba2/releases/v1.tar" {
+		t.Errorf("Expected error: unexpected target %+v", target)
+	}
+
+	if ParseSymlinkTarget(make(http.Header)) != nil {
+		t.Errorf("Expected error: %s", "Plain object was parsed as a symlink.")
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	if p := escapePath("bb94ba2/a b/c?d"); p != "bb94ba2/a%20b/c%3Fd" {
+		t.Errorf("Expected error: unexpected path %s", p)
+	}
+}
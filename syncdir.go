@@ -0,0 +1,290 @@
+package goswift
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SetMtime records the modification time of the uploaded file in
+// X-Object-Meta-Mtime, in the format python-swiftclient uses.
+func (m *Metadata) SetMtime(t time.Time) {
+	m.SetMeta("X-Object-Meta-Mtime", formatMtime(t))
+}
+
+func formatMtime(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/1000)
+}
+
+// headerMtime returns the file modification time stored with an object.
+func headerMtime(h http.Header) (time.Time, bool) {
+	v := h.Get("X-Object-Meta-Mtime")
+	if v == "" {
+		return time.Time{}, false
+	}
+	t, err := parseTimestamp(v)
+	return t, err == nil
+}
+
+// sameMtime compares modification times at the microsecond precision they
+// are stored with.
+func sameMtime(a time.Time, b time.Time) bool {
+	return a.Truncate(time.Microsecond).Equal(b.Truncate(time.Microsecond))
+}
+
+func fileMD5(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// SyncOptions controls SyncDir.
+type SyncOptions struct {
+	// Concurrency is the number of files uploaded at once. Defaults to 8.
+	Concurrency int
+	// Delete removes objects below the prefix that have no local file.
+	Delete bool
+	// DryRun only reports what would be uploaded and deleted.
+	DryRun bool
+	// Output receives a line for every upload and delete, e.g.
+	// "upload css/site.css". Nil discards them.
+	Output io.Writer
+}
+
+// SyncReport lists what SyncDir did, or would have done on a dry run.
+type SyncReport struct {
+	Uploaded  []string
+	Deleted   []string
+	Unchanged []string
+	Failed    []ObjectError
+}
+
+// SyncDir makes the objects of containerName below prefix match the files
+// below dir, like rsync. prefix is prepended to the slash separated file
+// paths as is, so it usually ends with "/".
+//
+// A file is uploaded when no object has its size, or when the object's
+// X-Object-Meta-Mtime differs and its MD5 does not match. Objects whose
+// content matches but whose mtime differs only get their mtime updated.
+// Files are uploaded with CreateObject, so large files are segmented, and
+// segments of replaced large objects are deleted.
+func (c *Client) SyncDir(dir string, containerName string, prefix string, opts SyncOptions) (*SyncReport, error) {
+	if err := c.prepare(); err != nil {
+		return nil, err
+	}
+	c.capabilities()
+	files := make(map[string]string)
+	dirs := make(map[string]bool)
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		name := prefix + filepath.ToSlash(rel)
+		if fi.IsDir() {
+			dirs[name] = true
+		} else if fi.Mode().IsRegular() {
+			files[name] = p
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !opts.DryRun {
+		if _, err := c.CreateContainer(containerName); err != nil {
+			return nil, err
+		}
+	}
+	objects, err := c.ListAllObjects(containerName, Params{Prefix: prefix})
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	remote := make(map[string]Object, len(objects))
+	for _, o := range objects {
+		remote[o.Name] = o
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	report := new(SyncReport)
+	var mu sync.Mutex
+	logf := func(format string, args ...interface{}) {
+		if opts.Output != nil {
+			fmt.Fprintf(opts.Output, format+"\n", args...)
+		}
+	}
+	parallel(opts.Concurrency, len(names), func(i int) {
+		name := names[i]
+		uploaded, err := c.syncFile(containerName, name, files[name], remote, opts.DryRun)
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err != nil:
+			report.Failed = append(report.Failed, ObjectError{Name: name, Err: err})
+		case uploaded:
+			logf("upload %s", name)
+			report.Uploaded = append(report.Uploaded, name)
+		default:
+			report.Unchanged = append(report.Unchanged, name)
+		}
+	})
+
+	if opts.Delete {
+		var extra []string
+		for _, o := range objects {
+			if files[o.Name] == "" && !dirs[strings.TrimSuffix(o.Name, "/")] {
+				extra = append(extra, o.Name)
+			}
+		}
+		parallel(opts.Concurrency, len(extra), func(i int) {
+			name := extra[i]
+			var err error
+			if !opts.DryRun {
+				if err = c.DeleteObject(containerName, name); isNotFound(err) {
+					err = nil
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Failed = append(report.Failed, ObjectError{Name: name, Err: err})
+			} else {
+				logf("delete %s", name)
+				report.Deleted = append(report.Deleted, name)
+			}
+		})
+	}
+
+	sort.Strings(report.Uploaded)
+	sort.Strings(report.Deleted)
+	sort.Strings(report.Unchanged)
+	sort.Slice(report.Failed, func(i, j int) bool { return report.Failed[i].Name < report.Failed[j].Name })
+	if len(report.Failed) != 0 {
+		return report, fmt.Errorf("Could not sync %d objects.", len(report.Failed))
+	}
+	return report, nil
+}
+
+// syncFile uploads the file at path as objectName unless the object already
+// matches it. It reports whether the file was, or on a dry run would be,
+// uploaded.
+func (c *Client) syncFile(containerName string, objectName string, path string, remote map[string]Object, dryRun bool) (bool, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	var header http.Header
+	if o, ok := remote[objectName]; ok {
+		header, err = c.ShowObjectMeta(containerName, objectName)
+		if err != nil && !isNotFound(err) {
+			return false, err
+		}
+		if header != nil && int64(o.Bytes) == fi.Size() {
+			if mtime, ok := headerMtime(header); ok && sameMtime(mtime, fi.ModTime()) {
+				return false, nil
+			}
+			if !isManifest(header) {
+				sum, err := fileMD5(path)
+				if err != nil {
+					return false, err
+				}
+				if sum == o.Hash {
+					if dryRun {
+						return false, nil
+					}
+					metadata := preservedMetadata(header)
+					metadata.SetMtime(fi.ModTime())
+					_, err := c.CreateObjectMeta(containerName, objectName, metadata)
+					return false, err
+				}
+			}
+		}
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	var old []SLOSegment
+	if header != nil && isManifest(header) {
+		if old, err = c.manifestSegments(containerName, objectName, header); err != nil {
+			return false, err
+		}
+	}
+	contentType, err := detectContentType(path)
+	if err != nil {
+		return false, err
+	}
+	metadata := NewMetadata()
+	metadata.SetMeta("Content-Type", contentType)
+	metadata.SetMtime(fi.ModTime())
+	if _, err := c.CreateObject(containerName, objectName, path, metadata); err != nil {
+		return false, err
+	}
+	if len(old) != 0 {
+		// Segments of an upload with the same mtime and size were
+		// overwritten in place and belong to the new manifest.
+		keep := ""
+		if segSize, err := c.segmentSize(fi.Size()); err == nil {
+			keep = fmt.Sprintf("/%s/%s", SegmentContainer(containerName), segmentPrefix(objectName, fi.ModTime(), fi.Size(), segSize))
+		}
+		var stale []SLOSegment
+		for _, s := range old {
+			if keep == "" || !strings.HasPrefix(s.Path, keep) {
+				stale = append(stale, s)
+			}
+		}
+		c.deleteSegments(stale)
+	}
+	return true, nil
+}
+
+// manifestSegments returns the segments a large object manifest refers to.
+func (c *Client) manifestSegments(containerName string, objectName string, header http.Header) ([]SLOSegment, error) {
+	if manifest := header.Get("X-Object-Manifest"); manifest != "" {
+		parts := strings.SplitN(manifest, "/", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid X-Object-Manifest: %s", manifest)
+		}
+		objects, err := c.ListAllObjects(parts[0], Params{Prefix: parts[1]})
+		if err != nil {
+			return nil, err
+		}
+		segments := make([]SLOSegment, len(objects))
+		for i, o := range objects {
+			segments[i] = SLOSegment{Path: fmt.Sprintf("/%s/%s", parts[0], o.Name), Etag: o.Hash, SizeBytes: int64(o.Bytes)}
+		}
+		return segments, nil
+	}
+	entries, _, err := c.GetSLOManifest(containerName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]SLOSegment, len(entries))
+	for i, e := range entries {
+		segments[i] = e.Segment()
+	}
+	return segments, nil
+}
@@ -0,0 +1,132 @@
+package goswift
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMtimeMetadata(t *testing.T) {
+	mtime := time.Unix(1414512342, 123456789)
+	metadata := NewMetadata()
+	metadata.SetMtime(mtime)
+	h := http.Header(metadata)
+	if v := h.Get("X-Object-Meta-Mtime"); v != "1414512342.123456" {
+		t.Errorf("Expected error: unexpected X-Object-Meta-Mtime %s", v)
+	}
+	got, ok := headerMtime(h)
+	if !ok || !sameMtime(got, mtime) {
+		t.Errorf("Expected error: mtime %s, expected %s", got, mtime)
+	}
+	if sameMtime(got, mtime.Add(time.Millisecond)) {
+		t.Errorf("Expected error: different mtimes compared equal")
+	}
+	if _, ok := headerMtime(http.Header{"X-Object-Meta-Mtime": {"yesterday"}}); ok {
+		t.Errorf("Expected error: invalid mtime was parsed")
+	}
+}
+
+func TestFileMD5(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goswift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "hello.txt")
+	if err := ioutil.WriteFile(name, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := fileMD5(name)
+	if err != nil || sum != "b1946ac92492d2347c6235b4d2611184" {
+		t.Errorf("Expected error: md5 %s, %v", sum, err)
+	}
+}
+
+func TestSyncDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goswift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	mtime := time.Unix(1414512342, 123456000)
+	for name, content := range map[string]string{"a.txt": "hello", "big.bin": "0123456789abcdef", "sub/b.txt": "world", "sub/c.txt": "same"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(p), 0755)
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(p, mtime, mtime)
+	}
+
+	s, c := newFakeSwift(t)
+	c.ChunkSize = 10
+	s.putObject("c", "site/a.txt", []byte("hello"), http.Header{"X-Object-Meta-Mtime": {formatMtime(mtime)}})
+	s.putObject("c", "site/sub/c.txt", []byte("same"), http.Header{"X-Object-Meta-Mtime": {"1000000000.000000"}})
+	s.putObject("c", "site/sub/", nil, http.Header{"Content-Type": {DirectoryType}})
+	s.putObject("c", "site/old.txt", []byte("old"), nil)
+	s.putObject("c_segments", "site/big.bin/slo/1/9/9/00000000", []byte("stale seg"), nil)
+	s.putObject("c", "site/big.bin", nil, http.Header{"X-Static-Large-Object": {"True"}})
+	s.object("c", "site/big.bin").manifest = []SLOSegment{{Path: "/c_segments/site/big.bin/slo/1/9/9/00000000",
+		Etag: md5Hex([]byte("stale seg")), SizeBytes: 9}}
+	writes := func() int {
+		return len(s.recorded("PUT")) + len(s.recorded("POST")) + len(s.recorded("DELETE"))
+	}
+
+	var out strings.Builder
+	report, err := c.SyncDir(dir, "c", "site/", SyncOptions{Delete: true, DryRun: true, Output: &out})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(report.Uploaded, report.Deleted, report.Unchanged) != "[site/big.bin site/sub/b.txt] [site/old.txt] [site/a.txt site/sub/c.txt]" {
+		t.Errorf("Expected error: unexpected dry run report %+v", report)
+	}
+	for _, line := range []string{"upload site/big.bin\n", "upload site/sub/b.txt\n", "delete site/old.txt\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected error: %q is missing from the output %q", line, out.String())
+		}
+	}
+	if n := writes(); n != 0 {
+		t.Errorf("Expected error: dry run made %d changes", n)
+	}
+
+	report, err = c.SyncDir(dir, "c", "site/", SyncOptions{Delete: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(report.Uploaded, report.Deleted, report.Unchanged) != "[site/big.bin site/sub/b.txt] [site/old.txt] [site/a.txt site/sub/c.txt]" {
+		t.Errorf("Expected error: unexpected report %+v", report)
+	}
+	for _, r := range s.recorded("PUT") {
+		if r.Path == "/v1/AUTH_test/c/site/a.txt" || r.Path == "/v1/AUTH_test/c/site/sub/c.txt" {
+			t.Errorf("Expected error: unchanged %s was uploaded", r.Path)
+		}
+	}
+	if o := s.object("c", "site/sub/c.txt"); o == nil || o.header.Get("X-Object-Meta-Mtime") != formatMtime(mtime) {
+		t.Errorf("Expected error: %s", "Mtime of matching object was not updated.")
+	}
+	if o := s.object("c", "site/sub/b.txt"); o == nil || string(o.data) != "world" || o.header.Get("X-Object-Meta-Mtime") != formatMtime(mtime) {
+		t.Errorf("Expected error: %s", "New file was not uploaded with its mtime.")
+	}
+	if s.object("c", "site/old.txt") != nil || s.object("c", "site/sub/") == nil {
+		t.Errorf("Expected error: unexpected objects %v", s.objectNames("c"))
+	}
+	o := s.object("c", "site/big.bin")
+	if o == nil || len(o.manifest) != 2 {
+		t.Fatalf("Expected error: %s", "Large file was not segmented.")
+	}
+	segments := s.objectNames("c_segments")
+	if len(segments) != 2 || strings.HasPrefix(segments[0], "site/big.bin/slo/1/") {
+		t.Errorf("Expected error: stale segments were not deleted: %v", segments)
+	}
+
+	// everything matches now
+	report, err = c.SyncDir(dir, "c", "site/", SyncOptions{Delete: true})
+	if err != nil || len(report.Unchanged) != 4 || len(report.Uploaded)+len(report.Deleted) != 0 {
+		t.Errorf("Expected error: unexpected report %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,218 @@
+package goswift
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MetaScope selects which X-*-Meta-* header prefix user metadata maps to.
+type MetaScope int
+
+const (
+	AccountScope MetaScope = iota
+	ContainerScope
+	ObjectScope
+)
+
+// Prefix returns the header prefix of the scope, e.g. "X-Object-Meta-".
+func (s MetaScope) Prefix() string {
+	switch s {
+	case AccountScope:
+		return "X-Account-Meta-"
+	case ContainerScope:
+		return "X-Container-Meta-"
+	default:
+		return "X-Object-Meta-"
+	}
+}
+
+// HeaderKey returns the full header name of a user metadata key.
+func (s MetaScope) HeaderKey(key string) string {
+	return http.CanonicalHeaderKey(s.Prefix() + foldMetaKey(key))
+}
+
+// MetaEncoding is how non-ASCII metadata values are sent to Swift.
+type MetaEncoding int
+
+const (
+	// RFC2047Encoding sends non-ASCII values as RFC 2047 encoded-words.
+	RFC2047Encoding MetaEncoding = iota
+	// PercentEncoding sends non-ASCII values percent-encoded.
+	PercentEncoding
+)
+
+// MetadataLimits mirrors Swift's constraints on user metadata.
+type MetadataLimits struct {
+	MaxNameLength  int
+	MaxValueLength int
+	MaxCount       int
+	MaxOverallSize int
+}
+
+// DefaultMetadataLimits are the defaults of Swift's swift.conf.
+var DefaultMetadataLimits = MetadataLimits{
+	MaxNameLength:  128,
+	MaxValueLength: 256,
+	MaxCount:       90,
+	MaxOverallSize: 4096,
+}
+
+// UserMetadata holds user metadata keyed by the name without the
+// X-*-Meta- prefix. Keys are case-folded to lower case.
+type UserMetadata map[string]string
+
+func foldMetaKey(key string) string {
+	return strings.ToLower(strings.Replace(key, "_", "-", -1))
+}
+
+func (u UserMetadata) Get(key string) string {
+	return u[foldMetaKey(key)]
+}
+
+func (u UserMetadata) Set(key, value string) {
+	u[foldMetaKey(key)] = value
+}
+
+func (u UserMetadata) Del(key string) {
+	delete(u, foldMetaKey(key))
+}
+
+// ParseUserMetadata extracts the user metadata of scope from h, decoding
+// RFC 2047 encoded values.
+func ParseUserMetadata(scope MetaScope, h http.Header) UserMetadata {
+	return DecodeUserMetadata(scope, RFC2047Encoding, h)
+}
+
+// DecodeUserMetadata extracts the user metadata of scope from h, decoding
+// values written with enc.
+func DecodeUserMetadata(scope MetaScope, enc MetaEncoding, h http.Header) UserMetadata {
+	prefix := scope.Prefix()
+	u := make(UserMetadata)
+	for k, v := range h {
+		k = http.CanonicalHeaderKey(k)
+		if len(v) > 0 && strings.HasPrefix(k, prefix) && len(k) > len(prefix) {
+			u.Set(k[len(prefix):], DecodeMetaValue(enc, v[0]))
+		}
+	}
+	return u
+}
+
+// Metadata converts u to request headers of scope after checking it against
+// DefaultMetadataLimits.
+func (u UserMetadata) Metadata(scope MetaScope) (Metadata, error) {
+	return u.Encode(scope, RFC2047Encoding, DefaultMetadataLimits)
+}
+
+// Encode converts u to request headers of scope, encoding values with enc
+// and checking the result against limits.
+func (u UserMetadata) Encode(scope MetaScope, enc MetaEncoding, limits MetadataLimits) (Metadata, error) {
+	encoded := make(UserMetadata, len(u))
+	for k, v := range u {
+		encoded.Set(k, EncodeMetaValue(enc, v))
+	}
+	if err := encoded.Validate(limits); err != nil {
+		return nil, err
+	}
+	metadata := NewMetadata()
+	for k, v := range encoded {
+		metadata.SetMeta(scope.HeaderKey(k), v)
+	}
+	return metadata, nil
+}
+
+// Validate checks u against limits the way the Swift proxy does.
+func (u UserMetadata) Validate(limits MetadataLimits) error {
+	if limits.MaxCount > 0 && len(u) > limits.MaxCount {
+		return fmt.Errorf("Too many metadata items; max %d", limits.MaxCount)
+	}
+	size := 0
+	for k, v := range u {
+		if k == "" {
+			return errors.New("Metadata name cannot be empty")
+		}
+		if !validHeaderToken(k) {
+			return fmt.Errorf("Metadata name %q contains invalid characters", k)
+		}
+		if limits.MaxNameLength > 0 && len(k) > limits.MaxNameLength {
+			return fmt.Errorf("Metadata name too long: %s; max %d", k, limits.MaxNameLength)
+		}
+		if limits.MaxValueLength > 0 && len(v) > limits.MaxValueLength {
+			return fmt.Errorf("Metadata value longer than %d: %s", limits.MaxValueLength, k)
+		}
+		size += len(k) + len(v)
+	}
+	if limits.MaxOverallSize > 0 && size > limits.MaxOverallSize {
+		return fmt.Errorf("Total metadata too large; max %d", limits.MaxOverallSize)
+	}
+	return nil
+}
+
+func validHeaderToken(s string) bool {
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if b <= ' ' || b >= 0x7f || strings.IndexByte(`()<>@,;:\"/[]?={}`, b) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// EncodeMetaValue makes v safe to send as a header value. ASCII values are
+// returned unchanged, except that with PercentEncoding values containing
+// '%' are escaped so they decode to themselves.
+func EncodeMetaValue(enc MetaEncoding, v string) string {
+	if enc == PercentEncoding {
+		if isASCII(v) && !strings.Contains(v, "%") {
+			return v
+		}
+		return url.PathEscape(v)
+	}
+	if isASCII(v) {
+		return v
+	}
+	return mime.QEncoding.Encode("utf-8", v)
+}
+
+// DecodeMetaValue reverses EncodeMetaValue. Values that are not encoded are
+// returned unchanged.
+func DecodeMetaValue(enc MetaEncoding, v string) string {
+	if enc == PercentEncoding {
+		if s, err := url.PathUnescape(v); err == nil {
+			return s
+		}
+		return v
+	}
+	dec := new(mime.WordDecoder)
+	if s, err := dec.DecodeHeader(v); err == nil {
+		return s
+	}
+	return v
+}
+
+// SetUserMeta sets the user metadata key of scope.
+func (m *Metadata) SetUserMeta(scope MetaScope, key, value string) {
+	m.SetMeta(scope.HeaderKey(key), EncodeMetaValue(RFC2047Encoding, value))
+}
+
+// SetDeleteUserMeta removes the user metadata key of scope.
+func (m *Metadata) SetDeleteUserMeta(scope MetaScope, key string) {
+	m.SetDeleteMeta(scope.HeaderKey(key))
+}
+
+// UserMeta returns the user metadata of scope held in m.
+func (m Metadata) UserMeta(scope MetaScope) UserMetadata {
+	return ParseUserMetadata(scope, http.Header(m))
+}
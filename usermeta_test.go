@@ -0,0 +1,80 @@
+package goswift
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUserMetadataRoundTrip(t *testing.T) {
+	u := make(UserMetadata)
+	u.Set("Book", "saka01")
+	u.Set("Sub_Title", "とんかつ")
+	metadata, err := u.Metadata(ContainerScope)
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	h := http.Header(metadata)
+	if h.Get("X-Container-Meta-Book") != "saka01" {
+		t.Errorf("Expected error: unexpected headers %v", h)
+	}
+	if v := h.Get("X-Container-Meta-Sub-Title"); !strings.HasPrefix(v, "=?utf-8?") {
+		t.Errorf("Expected error: value was not encoded: %s", v)
+	}
+	back := ParseUserMetadata(ContainerScope, h)
+	if back.Get("book") != "saka01" || back.Get("SUB-TITLE") != "とんかつ" {
+		t.Errorf("Expected error: unexpected metadata %v", back)
+	}
+	if len(ParseUserMetadata(ObjectScope, h)) != 0 {
+		t.Errorf("Expected error: %s", "Metadata leaked across scopes.")
+	}
+}
+
+func TestPercentEncodedMetaValue(t *testing.T) {
+	v := EncodeMetaValue(PercentEncoding, "ebi katsu丼")
+	if !isASCII(v) {
+		t.Errorf("Expected error: value was not encoded: %s", v)
+	}
+	if DecodeMetaValue(PercentEncoding, v) != "ebi katsu丼" {
+		t.Errorf("Expected error: could not decode %s", v)
+	}
+}
+
+func TestPercentEncodingRoundTrip(t *testing.T) {
+	for _, v := range []string{"100%", "100%25", "a%2Fb", "%zz", "plain", "50% 丼"} {
+		if got := DecodeMetaValue(PercentEncoding, EncodeMetaValue(PercentEncoding, v)); got != v {
+			t.Errorf("Expected error: %q came back as %q", v, got)
+		}
+	}
+	if v := EncodeMetaValue(PercentEncoding, "plain value"); v != "plain value" {
+		t.Errorf("Expected error: ASCII value was escaped: %q", v)
+	}
+}
+
+func TestUserMetadataLimits(t *testing.T) {
+	u := make(UserMetadata)
+	u.Set(strings.Repeat("a", 129), "x")
+	if err := u.Validate(DefaultMetadataLimits); err == nil {
+		t.Errorf("Expected error: %s", "Long name was accepted.")
+	}
+	u = make(UserMetadata)
+	u.Set("a", strings.Repeat("x", 257))
+	if _, err := u.Metadata(ObjectScope); err == nil {
+		t.Errorf("Expected error: %s", "Long value was accepted.")
+	}
+	u = make(UserMetadata)
+	for i := 0; i < 91; i++ {
+		u.Set(strings.Repeat("k", i+1), "v")
+	}
+	if err := u.Validate(DefaultMetadataLimits); err == nil {
+		t.Errorf("Expected error: %s", "Too many items were accepted.")
+	}
+}
+
+func TestSetDeleteUserMeta(t *testing.T) {
+	metadata := NewMetadata()
+	metadata.SetDeleteUserMeta(ObjectScope, "book")
+	if http.Header(metadata).Get("X-Remove-Object-Meta-Book") != "x" {
+		t.Errorf("Expected error: unexpected headers %v", metadata)
+	}
+}
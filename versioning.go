@@ -0,0 +1,174 @@
+package goswift
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// VersioningConfig is the versioning state of a container. Enabled is the
+// object versioning API (X-Versions-Enabled); VersionsLocation and
+// HistoryLocation are the legacy versioned_writes archive containers.
+type VersioningConfig struct {
+	Enabled          bool
+	VersionsLocation string
+	HistoryLocation  string
+}
+
+func (c *Client) GetVersioning(containerName string) (VersioningConfig, error) {
+	header, err := c.ShowContainerMeta(containerName)
+	if err != nil {
+		return VersioningConfig{}, err
+	}
+	h := http.Header(header)
+	enabled, _ := strconv.ParseBool(h.Get("X-Versions-Enabled"))
+	return VersioningConfig{
+		Enabled:          enabled,
+		VersionsLocation: h.Get("X-Versions-Location"),
+		HistoryLocation:  h.Get("X-History-Location"),
+	}, nil
+}
+
+func (v VersioningConfig) archiveContainer() string {
+	if v.VersionsLocation != "" {
+		return v.VersionsLocation
+	}
+	return v.HistoryLocation
+}
+
+// EnableVersioning turns on the object versioning API for the container.
+func (c *Client) EnableVersioning(containerName string) (http.Header, error) {
+	metadata := NewMetadata()
+	metadata.SetMeta("X-Versions-Enabled", "true")
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+// DisableVersioning suspends the object versioning API for the container.
+// Existing versions are kept.
+func (c *Client) DisableVersioning(containerName string) (http.Header, error) {
+	metadata := NewMetadata()
+	metadata.SetMeta("X-Versions-Enabled", "false")
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+// SetVersionsLocation enables legacy "stack" versioning: overwritten objects
+// are archived in archiveContainer and deleting an object restores its
+// previous version.
+func (c *Client) SetVersionsLocation(containerName string, archiveContainer string) (http.Header, error) {
+	metadata := NewMetadata()
+	metadata.SetMeta("X-Versions-Location", archiveContainer)
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+// SetHistoryLocation enables legacy "history" versioning: overwritten and
+// deleted objects are archived in archiveContainer.
+func (c *Client) SetHistoryLocation(containerName string, archiveContainer string) (http.Header, error) {
+	metadata := NewMetadata()
+	metadata.SetMeta("X-History-Location", archiveContainer)
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+// RemoveVersionsLocation disables legacy versioning of either mode.
+func (c *Client) RemoveVersionsLocation(containerName string) (http.Header, error) {
+	metadata := NewMetadata()
+	metadata.SetDeleteMeta("X-Versions-Location")
+	metadata.SetDeleteMeta("X-History-Location")
+	return c.CreateContainerMeta(containerName, metadata)
+}
+
+// ObjectVersion is an entry of a ?versions listing.
+type ObjectVersion struct {
+	Hash         string
+	LastModified string `json:"last_modified"`
+	Bytes        uint
+	Name         string
+	ContentType  string `json:"content_type"`
+	VersionId    string `json:"version_id"`
+	IsLatest     bool   `json:"is_latest"`
+}
+
+// ListObjectVersions lists every version of the objects of a container with
+// the object versioning API. Use Params.Marker and Params.VersionMarker to
+// page through the listing.
+func (c *Client) ListObjectVersions(containerName string, p Params) ([]ObjectVersion, http.Header, error) {
+	params := make(url.Values)
+	p.setQueryParams(params)
+	params.Set("versions", "")
+	var versions []ObjectVersion
+	body, header, err := c.request("GET", containerName, nil, 0, nil, params)
+	if body != nil {
+		json.Unmarshal(body, &versions)
+	}
+	return versions, header, err
+}
+
+func versionParams(versionId string) url.Values {
+	params := make(url.Values)
+	params.Set("version-id", versionId)
+	return params
+}
+
+func (c *Client) GetObjectVersion(containerName string, objectName string, versionId string) ([]byte, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	resbody, _, err := c.request("GET", objectPath, nil, 0, nil, versionParams(versionId))
+	return resbody, err
+}
+
+func (c *Client) ShowObjectVersionMeta(containerName string, objectName string, versionId string) (http.Header, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	_, header, err := c.request("HEAD", objectPath, nil, 0, nil, versionParams(versionId))
+	return header, err
+}
+
+func (c *Client) DeleteObjectVersion(containerName string, objectName string, versionId string) error {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	_, _, err := c.request("DELETE", objectPath, nil, 0, nil, versionParams(versionId))
+	return err
+}
+
+// RestoreObjectVersion makes versionId the current version of the object by
+// copying it over itself.
+func (c *Client) RestoreObjectVersion(containerName string, objectName string, versionId string) (http.Header, error) {
+	objectPath := fmt.Sprintf("%s/%s", containerName, objectName)
+	metadata := NewMetadata()
+	metadata.SetMeta("Destination", objectPath)
+	_, header, err := c.request("COPY", objectPath, nil, 0, http.Header(metadata), versionParams(versionId))
+	return header, err
+}
+
+// LegacyVersionPrefix returns the prefix legacy versioning archives versions
+// of objectName under.
+func LegacyVersionPrefix(objectName string) string {
+	return fmt.Sprintf("%03x%s/", len(objectName), objectName)
+}
+
+// ListLegacyVersions lists the archived versions of an object of a container
+// using legacy versioning, oldest first.
+func (c *Client) ListLegacyVersions(containerName string, objectName string) ([]Object, error) {
+	config, err := c.GetVersioning(containerName)
+	if err != nil {
+		return nil, err
+	}
+	archive := config.archiveContainer()
+	if archive == "" {
+		return nil, fmt.Errorf("Container %s has no legacy versioning.", containerName)
+	}
+	objects, _, err := c.ListObjectsWithParams(archive, Params{Prefix: LegacyVersionPrefix(objectName)})
+	return objects, err
+}
+
+// RestoreLegacyVersion copies an archived version, as listed by
+// ListLegacyVersions, back over the object.
+func (c *Client) RestoreLegacyVersion(containerName string, objectName string, archivedName string) (http.Header, error) {
+	config, err := c.GetVersioning(containerName)
+	if err != nil {
+		return nil, err
+	}
+	archive := config.archiveContainer()
+	if archive == "" {
+		return nil, fmt.Errorf("Container %s has no legacy versioning.", containerName)
+	}
+	return c.CopyObject(archive, archivedName, containerName, objectName)
+}
@@ -0,0 +1,40 @@
+package goswift
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestLegacyVersionPrefix(t *testing.T) {
+	if p := LegacyVersionPrefix("dd94ba2.json"); p != "00cdd94ba2.json/" {
+		t.Errorf("Expected error: unexpected prefix %s", p)
+	}
+}
+
+func TestParseObjectVersions(t *testing.T) {
+	body := []byte(`[{"name": "dd94ba2.json", "hash": "d41d8cd98f00b204e9800998ecf8427e", "bytes": 0, "content_type": "application/json", "last_modified": "2020-01-01T00:00:00.000000", "version_id": "1577836800.00000", "is_latest": true}]`)
+	var versions []ObjectVersion
+	if err := json.Unmarshal(body, &versions); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if len(versions) != 1 || versions[0].VersionId != "1577836800.00000" || !versions[0].IsLatest {
+		t.Errorf("Expected error: unexpected versions %+v", versions)
+	}
+}
+
+func TestSetQueryParams(t *testing.T) {
+	params := make(url.Values)
+	p := Params{Path: "photos", VersionMarker: "1577836800.00000"}
+	p.setQueryParams(params)
+	if params.Get("path") != "photos" || params.Get("version_marker") != "1577836800.00000" {
+		t.Errorf("Expected error: unexpected query %s", params.Encode())
+	}
+	// path used to be sent only when Endmarker was set
+	params = make(url.Values)
+	p = Params{Endmarker: "m"}
+	p.setQueryParams(params)
+	if params.Get("end_marker") != "m" || params.Has("path") {
+		t.Errorf("Expected error: unexpected query %s", params.Encode())
+	}
+}
@@ -0,0 +1,205 @@
+package goswift
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// fsWriteBuffer is how much a file created with WriteFS keeps in memory
+// before spilling to a temporary file.
+const fsWriteBuffer = 8 << 20
+
+// WriteFS is an FS that can also create, remove and rename objects and
+// create directories, so it can stand in for a local directory.
+type WriteFS struct {
+	*FS
+}
+
+// WriteFS returns a writable file system over the objects of containerName.
+func (c *Client) WriteFS(containerName string) *WriteFS {
+	return &WriteFS{FS: c.FS(containerName)}
+}
+
+// Create returns a file that is uploaded as the object name when it is
+// closed, replacing the object if it exists. Writes are buffered in memory
+// and then in a temporary file, and large files are uploaded in segments.
+// Unlike on local disk, the parent directory does not have to exist.
+func (w *WriteFS) Create(name string) (io.WriteCloser, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: fs.ErrInvalid}
+	}
+	if info, err := w.stat("create", name); err == nil && info.IsDir() {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: errors.New("is a directory")}
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return &fsWriter{fs: w, name: name}, nil
+}
+
+// Mkdir creates a directory marker object of type application/directory,
+// as other Swift tools do. The parent directory has to exist.
+func (w *WriteFS) Mkdir(name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if _, err := w.stat("mkdir", name); err == nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if parent := path.Dir(name); parent != "." {
+		info, err := w.stat("mkdir", parent)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: name, Err: errors.New("parent is not a directory")}
+		}
+	}
+	if _, err := w.client.CreateDirectoryMarker(w.container, name, DirectoryType); err != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fsError(err)}
+	}
+	return nil
+}
+
+// Remove removes an object or an empty directory along with its marker.
+func (w *WriteFS) Remove(name string) error {
+	info, err := w.stat("remove", name)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		if err := w.client.DeleteObject(w.container, name); err != nil {
+			return &fs.PathError{Op: "remove", Path: name, Err: fsError(err)}
+		}
+		return nil
+	}
+	if name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	objects, _, err := w.client.ListObjectsWithParams(w.container, Params{Prefix: name + "/", Limit: 2})
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: fsError(err)}
+	}
+	for _, o := range objects {
+		if o.Name != name+"/" {
+			return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+		}
+	}
+	for _, marker := range []string{name, name + "/"} {
+		if err := w.client.DeleteObject(w.container, marker); err != nil && !isNotFound(err) {
+			return &fs.PathError{Op: "remove", Path: name, Err: fsError(err)}
+		}
+	}
+	return nil
+}
+
+// Rename moves an object, or a directory with everything below it, to
+// newname. Swift has no rename, so objects are copied and then deleted.
+func (w *WriteFS) Rename(oldname string, newname string) error {
+	if !fs.ValidPath(newname) || newname == "." {
+		return &fs.PathError{Op: "rename", Path: newname, Err: fs.ErrInvalid}
+	}
+	info, err := w.stat("rename", oldname)
+	if err != nil {
+		return err
+	}
+	if oldname == "." {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrInvalid}
+	}
+	if info.IsDir() && (strings.HasPrefix(newname+"/", oldname+"/") || strings.HasPrefix(oldname+"/", newname+"/")) {
+		// MovePrefix refuses overlapping prefixes, so check before the
+		// marker is moved.
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrInvalid}
+	}
+	if !info.IsDir() || info.Sys() != nil {
+		// a file, or the marker object of a directory
+		if _, err := w.client.MoveObject(w.container, oldname, w.container, newname); err != nil {
+			return &fs.PathError{Op: "rename", Path: oldname, Err: fsError(err)}
+		}
+		if !info.IsDir() {
+			return nil
+		}
+	}
+	if _, err := w.client.MovePrefix(w.container, oldname+"/", w.container, newname+"/", MoveOptions{}); err != nil {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fsError(err)}
+	}
+	return nil
+}
+
+// fsWriter buffers a file created with WriteFS and uploads it on Close.
+type fsWriter struct {
+	fs     *WriteFS
+	name   string
+	buf    bytes.Buffer
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+func (f *fsWriter) Write(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.file == nil && f.buf.Len()+len(p) > fsWriteBuffer {
+		file, err := ioutil.TempFile("", "goswift-")
+		if err != nil {
+			return 0, &fs.PathError{Op: "write", Path: f.name, Err: err}
+		}
+		f.file = file
+		if _, err := f.buf.WriteTo(file); err != nil {
+			return 0, &fs.PathError{Op: "write", Path: f.name, Err: err}
+		}
+	}
+	var n int
+	var err error
+	if f.file != nil {
+		n, err = f.file.Write(p)
+	} else {
+		n, err = f.buf.Write(p)
+	}
+	f.size += int64(n)
+	if err != nil {
+		return n, &fs.PathError{Op: "write", Path: f.name, Err: err}
+	}
+	return n, nil
+}
+
+// Close uploads the file. The object is not changed if the upload fails.
+func (f *fsWriter) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	var r io.ReaderAt = bytes.NewReader(f.buf.Bytes())
+	if f.file != nil {
+		defer os.Remove(f.file.Name())
+		defer f.file.Close()
+		r = f.file
+	}
+	head := make([]byte, 512)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return &fs.PathError{Op: "close", Path: f.name, Err: err}
+	}
+	contentType := mime.TypeByExtension(path.Ext(f.name))
+	if contentType == "" {
+		contentType = http.DetectContentType(head[:n])
+	}
+	metadata := NewMetadata()
+	metadata.SetMeta("Content-Type", contentType)
+	c := f.fs.client
+	if _, err := c.uploadObject(f.fs.container, f.name, r, f.size, time.Now(), metadata); err != nil {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fsError(err)}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package goswift
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWriteFSInvalidPath(t *testing.T) {
+	fsys := new(Client).WriteFS("test")
+	if _, err := fsys.Create("a/../b"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Expected error: invalid name was not rejected: %v", err)
+	}
+	if err := fsys.Mkdir("."); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Expected error: invalid name was not rejected: %v", err)
+	}
+	if err := fsys.Rename("a", "/b"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Expected error: invalid name was not rejected: %v", err)
+	}
+}
+
+func TestFSWriterSpill(t *testing.T) {
+	f := &fsWriter{name: "big.bin"}
+	small := bytes.Repeat([]byte("a"), 1024)
+	if _, err := f.Write(small); err != nil || f.file != nil {
+		t.Fatalf("Expected error: small write was not buffered in memory: %v", err)
+	}
+	big := bytes.Repeat([]byte("b"), fsWriteBuffer)
+	if _, err := f.Write(big); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if f.file == nil {
+		t.Fatalf("Expected error: large write did not spill to a file")
+	}
+	defer os.Remove(f.file.Name())
+	defer f.file.Close()
+	if f.size != int64(len(small)+len(big)) || f.buf.Len() != 0 {
+		t.Errorf("Expected error: size %d, buffered %d", f.size, f.buf.Len())
+	}
+	b, err := ioutil.ReadFile(f.file.Name())
+	if err != nil || !bytes.Equal(b, append(small, big...)) {
+		t.Errorf("Expected error: spilled content differs: %v", err)
+	}
+
+	f.closed = true
+	if _, err := f.Write(small); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("Expected error: write after close was accepted: %v", err)
+	}
+}
+
+func TestWriteFSRoundTrip(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "placeholder", nil, nil)
+	fsys := c.WriteFS("c")
+	if err := fsys.Mkdir("docs"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if o := s.object("c", "docs"); o == nil || o.header.Get("Content-Type") != DirectoryType {
+		t.Errorf("Expected error: %s", "Directory marker was not created.")
+	}
+	if err := fsys.Mkdir("docs"); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Expected error: existing directory was created again: %v", err)
+	}
+	if err := fsys.Mkdir("missing/docs"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected error: directory was created without its parent: %v", err)
+	}
+
+	f, err := fsys.Create("docs/hello.txt")
+	if err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	f.Write([]byte("hello "))
+	f.Write([]byte("world"))
+	if s.object("c", "docs/hello.txt") != nil {
+		t.Errorf("Expected error: %s", "Object was uploaded before Close.")
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if b, err := fs.ReadFile(fsys, "docs/hello.txt"); err != nil || string(b) != "hello world" {
+		t.Errorf("Expected error: read %q: %v", b, err)
+	}
+	if o := s.object("c", "docs/hello.txt"); o == nil || !strings.HasPrefix(o.header.Get("Content-Type"), "text/plain") {
+		t.Errorf("Expected error: %s", "Content type was not set.")
+	}
+
+	if err := fsys.Remove("docs"); err == nil {
+		t.Errorf("Expected error: %s", "Non-empty directory was removed.")
+	}
+	if s.object("c", "docs") == nil || s.object("c", "docs/hello.txt") == nil {
+		t.Errorf("Expected error: %s", "Failed remove changed the directory.")
+	}
+
+	if err := fsys.Rename("docs/hello.txt", "docs/hi.txt"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if s.object("c", "docs/hello.txt") != nil || s.object("c", "docs/hi.txt") == nil {
+		t.Errorf("Expected error: %s", "File was not renamed.")
+	}
+	if err := fsys.Rename("docs", "docs/sub"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Expected error: directory was moved into itself: %v", err)
+	}
+	if err := fsys.Rename("docs", "text"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if names := strings.Join(s.objectNames("c"), ","); names != "placeholder,text,text/hi.txt" {
+		t.Errorf("Expected error: unexpected objects %s", names)
+	}
+	if info, err := fs.Stat(fsys, "text"); err != nil || !info.IsDir() {
+		t.Errorf("Expected error: renamed directory is not a directory: %v", err)
+	}
+
+	if err := fsys.Remove("text/hi.txt"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if err := fsys.Remove("text"); err != nil {
+		t.Fatalf("Expected error: %s", err)
+	}
+	if names := strings.Join(s.objectNames("c"), ","); names != "placeholder" {
+		t.Errorf("Expected error: unexpected objects %s", names)
+	}
+}
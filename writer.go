@@ -0,0 +1,280 @@
+package goswift
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultWriterChunkSize is the segment size of an ObjectWriter when
+	// Client.ChunkSize is not set.
+	defaultWriterChunkSize   = 100 << 20
+	defaultWriterConcurrency = 2
+)
+
+// WriterOptions controls NewWriter.
+type WriterOptions struct {
+	// Metadata is sent with the object, or with the manifest of a large
+	// object, e.g. its Content-Type.
+	Metadata Metadata
+	// Concurrency is the number of segments uploaded at once. Every
+	// segment in flight is held in memory. Defaults to 2.
+	Concurrency int
+	// SegmentContainer is where segments are uploaded. Defaults to
+	// SegmentContainer(containerName).
+	SegmentContainer string
+}
+
+// ObjectWriter uploads what is written to it as an object. See NewWriter.
+type ObjectWriter struct {
+	c           *Client
+	ctx         context.Context
+	cancel      context.CancelFunc
+	container   string
+	object      string
+	opts        WriterOptions
+	chunkSize   int64
+	maxSegments int
+	buf         []byte
+	sem         chan struct{}
+	wg          sync.WaitGroup
+	prefix      string
+	closed      bool
+
+	mu       sync.Mutex
+	segments []SLOSegment
+	err      error
+}
+
+// NewWriter returns a writer that uploads to objectName. Up to the client's
+// ChunkSize (100 MiB if unset) is buffered and sent in a single PUT on
+// Close. Once more is written, the data is uploaded as static large object
+// segments in the background while writing continues, and Close commits
+// the manifest. The object is only replaced when Close succeeds; Abort, a
+// failed upload or cancelling ctx removes the segments again.
+func (c *Client) NewWriter(ctx context.Context, containerName string, objectName string, opts WriterOptions) *ObjectWriter {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultWriterConcurrency
+	}
+	if opts.SegmentContainer == "" {
+		opts.SegmentContainer = SegmentContainer(containerName)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &ObjectWriter{
+		c:         c,
+		ctx:       ctx,
+		cancel:    cancel,
+		container: containerName,
+		object:    objectName,
+		opts:      opts,
+		sem:       make(chan struct{}, opts.Concurrency),
+	}
+}
+
+func (w *ObjectWriter) failure() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *ObjectWriter) fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+// init picks the segment size once the first data arrives, so the
+// capabilities are only fetched for writers that are used.
+func (w *ObjectWriter) init() error {
+	if w.chunkSize != 0 {
+		return nil
+	}
+	if err := w.c.prepare(); err != nil {
+		return err
+	}
+	size := int64(w.c.ChunkSize)
+	if size == 0 {
+		size = defaultWriterChunkSize
+	}
+	caps := w.c.capabilities()
+	if caps.SLO != nil {
+		if caps.SLO.MinSegmentSize > size {
+			size = caps.SLO.MinSegmentSize
+		}
+		w.maxSegments = caps.SLO.MaxManifestSegments
+	}
+	if limit := w.c.maxFileSize(); size > limit {
+		size = limit
+	}
+	w.chunkSize = size
+	return nil
+}
+
+func (w *ObjectWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, errors.New("Writer is closed.")
+	}
+	if err := w.failure(); err != nil {
+		return 0, err
+	}
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if err := w.init(); err != nil {
+		return 0, err
+	}
+	n := 0
+	for len(p) > 0 {
+		if int64(len(w.buf)) == w.chunkSize {
+			if err := w.flush(); err != nil {
+				return n, err
+			}
+		}
+		c := int64(len(p))
+		if room := w.chunkSize - int64(len(w.buf)); c > room {
+			c = room
+		}
+		w.buf = append(w.buf, p[:c]...)
+		p = p[c:]
+		n += int(c)
+	}
+	return n, nil
+}
+
+// flush uploads the buffer as the next segment in the background. It blocks
+// while opts.Concurrency segments are in flight. Errors are recorded with
+// fail, so Close cannot commit an object that is missing data.
+func (w *ObjectWriter) flush() error {
+	if w.prefix == "" {
+		if _, err := w.c.CreateContainer(w.opts.SegmentContainer); err != nil {
+			w.fail(err)
+			return err
+		}
+		stamp := time.Now()
+		// The size is not known up front, so unlike segmentPrefix it is
+		// left out.
+		w.prefix = fmt.Sprintf("%s/slo/%d.%06d/%d/", w.object, stamp.Unix(), stamp.Nanosecond()/1000, w.chunkSize)
+	}
+	w.mu.Lock()
+	index := len(w.segments)
+	w.segments = append(w.segments, SLOSegment{})
+	w.mu.Unlock()
+	if w.maxSegments > 0 && index >= w.maxSegments {
+		err := fmt.Errorf("Object exceeds the cluster's limit of %d segments.", w.maxSegments)
+		w.fail(err)
+		return err
+	}
+	select {
+	case w.sem <- struct{}{}:
+	case <-w.ctx.Done():
+		w.fail(w.ctx.Err())
+		return w.ctx.Err()
+	}
+	data := w.buf
+	w.buf = nil
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer func() { <-w.sem }()
+		name := fmt.Sprintf("%s%08d", w.prefix, index)
+		segment, err := w.c.putSegment(w.ctx, w.opts.SegmentContainer, name, bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			w.fail(err)
+			w.cancel()
+			return
+		}
+		w.mu.Lock()
+		w.segments[index] = segment
+		w.mu.Unlock()
+	}()
+	return nil
+}
+
+// Close uploads what is left and commits the object. If that fails, the
+// uploaded segments are removed.
+func (w *ObjectWriter) Close() error {
+	if w.closed {
+		return errors.New("Writer is closed.")
+	}
+	w.closed = true
+	defer w.cancel()
+	if err := w.failure(); err != nil {
+		w.cleanup()
+		return err
+	}
+	if err := w.init(); err != nil {
+		return err
+	}
+	header := http.Header{}
+	for k, v := range w.opts.Metadata {
+		header[k] = v
+	}
+	if w.prefix == "" {
+		sum := md5.Sum(w.buf)
+		header.Set("Etag", hex.EncodeToString(sum[:]))
+		objectPath := fmt.Sprintf("%s/%s", w.container, w.object)
+		var body io.Reader
+		if len(w.buf) > 0 {
+			body = bytes.NewReader(w.buf)
+		}
+		_, _, err := w.c.requestContext(w.ctx, "PUT", objectPath, body, int64(len(w.buf)), header, nil)
+		return err
+	}
+	if len(w.buf) > 0 {
+		if err := w.flush(); err != nil {
+			w.cleanup()
+			return err
+		}
+	}
+	w.wg.Wait()
+	if err := w.failure(); err != nil {
+		w.cleanup()
+		return err
+	}
+	var err error
+	if w.c.useDLO() {
+		_, err = w.c.createDLOManifest(w.ctx, w.container, w.object, w.opts.SegmentContainer, w.prefix, Metadata(header))
+	} else {
+		_, err = w.c.createSLOManifest(w.ctx, w.container, w.object, w.segments, Metadata(header))
+	}
+	if err != nil {
+		w.cleanup()
+		return err
+	}
+	return nil
+}
+
+// Abort stops the upload and removes the segments uploaded so far. The
+// object is left as it was.
+func (w *ObjectWriter) Abort() error {
+	if w.closed {
+		return errors.New("Writer is closed.")
+	}
+	w.closed = true
+	w.cleanup()
+	return nil
+}
+
+// cleanup waits for segment uploads to finish and deletes them.
+func (w *ObjectWriter) cleanup() {
+	w.cancel()
+	w.wg.Wait()
+	w.buf = nil
+	var uploaded []SLOSegment
+	for _, s := range w.segments {
+		if s.Path != "" {
+			uploaded = append(uploaded, s)
+		}
+	}
+	w.c.deleteSegments(uploaded)
+}
@@ -0,0 +1,120 @@
+package goswift
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewWriterDefaults(t *testing.T) {
+	w := new(Client).NewWriter(context.Background(), "test", "big.bin", WriterOptions{})
+	if w.opts.Concurrency != defaultWriterConcurrency || w.opts.SegmentContainer != "test_segments" || cap(w.sem) != defaultWriterConcurrency {
+		t.Errorf("Expected error: unexpected options %+v", w.opts)
+	}
+}
+
+func TestObjectWriterBuffersChunk(t *testing.T) {
+	w := new(Client).NewWriter(context.Background(), "test", "small.txt", WriterOptions{})
+	w.chunkSize = 10
+	for _, s := range []string{"0123", "456789"} {
+		if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Expected error: wrote %d bytes, %v", n, err)
+		}
+	}
+	if !bytes.Equal(w.buf, []byte("0123456789")) || w.prefix != "" {
+		t.Errorf("Expected error: a full chunk was not kept for a single PUT")
+	}
+	if err := w.Abort(); err != nil {
+		t.Errorf("Expected error: %s", err)
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Errorf("Expected error: write after abort was accepted")
+	}
+	if err := w.Close(); err == nil {
+		t.Errorf("Expected error: close after abort was accepted")
+	}
+}
+
+func TestObjectWriterCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := new(Client).NewWriter(ctx, "test", "small.txt", WriterOptions{})
+	w.chunkSize = 10
+	cancel()
+	if _, err := w.Write([]byte("x")); err != context.Canceled {
+		t.Errorf("Expected error: expected context.Canceled, got %v", err)
+	}
+}
+
+func TestObjectWriterSegments(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "placeholder", nil, nil)
+	c.ChunkSize = 4
+	w := c.NewWriter(context.Background(), "c", "big.bin", WriterOptions{Metadata: Metadata{"Content-Type": {"text/plain"}}})
+	for _, p := range []string{"012", "3456", "789"} {
+		if _, err := w.Write([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if segments := s.objectNames("c_segments"); len(segments) != 3 || !strings.HasPrefix(segments[0], "big.bin/slo/") {
+		t.Errorf("Expected error: unexpected segments %v", segments)
+	}
+	o := s.object("c", "big.bin")
+	if o == nil || len(o.manifest) != 3 || o.header.Get("Content-Type") != "text/plain" {
+		t.Fatalf("Expected error: %s", "Manifest was not committed.")
+	}
+	body, _, err := c.GetObjectReader("c", "big.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if b, _ := ioutil.ReadAll(body); string(b) != "0123456789" {
+		t.Errorf("Expected error: unexpected content %q", b)
+	}
+}
+
+func TestObjectWriterAbort(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "placeholder", nil, nil)
+	c.ChunkSize = 4
+	w := c.NewWriter(context.Background(), "c", "big.bin", WriterOptions{})
+	if _, err := w.Write([]byte("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	if segments := s.objectNames("c_segments"); len(segments) != 0 {
+		t.Errorf("Expected error: segments were left behind: %v", segments)
+	}
+	if s.object("c", "big.bin") != nil {
+		t.Errorf("Expected error: %s", "Aborted object was created.")
+	}
+}
+
+func TestObjectWriterSegmentContainerError(t *testing.T) {
+	s, c := newFakeSwift(t)
+	s.putObject("c", "placeholder", nil, nil)
+	s.fail = func(r *http.Request) int {
+		if r.Method == "PUT" && r.URL.Path == "/v1/AUTH_test/c_segments" {
+			return http.StatusForbidden
+		}
+		return 0
+	}
+	c.ChunkSize = 4
+	w := c.NewWriter(context.Background(), "c", "big.bin", WriterOptions{})
+	if _, err := w.Write([]byte("0123456789")); err == nil {
+		t.Errorf("Expected error: %s", "Write succeeded without a segment container.")
+	}
+	if err := w.Close(); err == nil {
+		t.Errorf("Expected error: %s", "Close committed a truncated object.")
+	}
+	if s.object("c", "big.bin") != nil {
+		t.Errorf("Expected error: %s", "Truncated object was created.")
+	}
+}